@@ -2,8 +2,8 @@ package errors
 
 import (
 	"encoding/json"
-	"fmt"
 	"runtime"
+	"sync"
 )
 
 type (
@@ -11,61 +11,127 @@ type (
 		File     string `json:"file"`
 		Line     int    `json:"line"`
 		Function string `json:"function"`
+
+		// path is the absolute file path before trimming.
+		// It is printed by StackString, File is printed by the others.
+		path string
 	}
+
+	// callerInfo holds the raw program counters of the callers.
+	// The frames are resolved on the first call of Items.
 	callerInfo struct {
-		Items       []*callerInfoItem `json:"items"`
-		outputCount int
+		pcs   []uintptr
+		once  sync.Once
+		items []*callerInfoItem
 	}
 )
 
 var (
 	// CallerInfoMaxStack is maximum stack count for caller info.
+	//
+	// Deprecated: Use Printer.WithMaxStack and SetDefaultPrinter.
 	CallerInfoMaxStack = 10
+
+	// frameCache caches the resolved frame of each program counter.
+	frameCache sync.Map
 )
 
+// String returns the first location by the default printer.
 func (c *callerInfo) String() string {
-	if len(c.Items) <= 0 {
-		return ""
+	return DefaultPrinter().location(c)
+}
+
+// Items returns the frames of the callers.
+// The items must not be modified, they are shared by the frame cache.
+func (c *callerInfo) Items() []*callerInfoItem {
+	c.once.Do(c.resolve)
+	return c.items
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (c *callerInfo) UnmarshalJSON(b []byte) error {
+	items := []*callerInfoItem{}
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
 	}
+	c.once.Do(func() {
+		c.items = items
+	})
+	return nil
+}
 
-	first := c.Items[0]
-	return fmt.Sprintf("%s:%d:%s", first.File, first.Line, first.Function)
+// resolvedCallerInfo returns a new callerInfo which has the items.
+func resolvedCallerInfo(items []*callerInfoItem) *callerInfo {
+	c := &callerInfo{}
+	c.once.Do(func() {
+		c.items = items
+	})
+	return c
 }
 
-func (c *callerInfo) MarshalJSON() ([]byte, error) {
-	return json.Marshal(c.Items[:c.outputCount])
+func (c *callerInfo) resolve() {
+	c.items = make([]*callerInfoItem, len(c.pcs))
+	for index, pc := range c.pcs {
+		c.items[index] = frameOf(pc)
+	}
 }
 
-func caller(skip int) *callerInfo {
-	pcs := make([]uintptr, CallerInfoMaxStack)
-	n := runtime.Callers(skip+2, pcs)
-	pcs = pcs[:n]
+// frameOf returns the frame of the pc returned by runtime.Callers.
+// Each pc from runtime.Callers is a logical frame, inlined or not,
+// so runtime.CallersFrames resolves it to exactly one frame.
+func frameOf(pc uintptr) *callerInfoItem {
+	if item, ok := frameCache.Load(pc); ok {
+		return item.(*callerInfoItem)
+	}
 
-	result := &callerInfo{
-		Items: make([]*callerInfoItem, len(pcs)),
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	item := &callerInfoItem{
+		Function: frame.Function,
+		File:     TrimModulePath(frame.File, frame.Function),
+		Line:     frame.Line,
+		path:     frame.File,
 	}
+	actual, _ := frameCache.LoadOrStore(pc, item)
+	return actual.(*callerInfoItem)
+}
+
+const (
+	panicFunction = "runtime.gopanic"
 
-	for index, pc := range pcs {
-		f := runtime.FuncForPC(pc)
-		function := f.Name()
-		file, line := f.FileLine(pc)
-		item := &callerInfoItem{
-			Function: function,
-			File:     trimGOPATHProbably(file, function),
-			Line:     line,
+	// panicFramesMargin is the frame count for the deferred functions
+	// between the recover and the panicking function.
+	panicFramesMargin = 32
+)
+
+func caller(skip int) *callerInfo {
+	return callerWithMax(skip+1, captureMaxStack())
+}
+
+// panicCaller returns the caller info of the panicking function.
+// It must be called in a deferred function while panicking.
+func panicCaller() *callerInfo {
+	maxStack := captureMaxStack()
+	result := callerWithMax(1, maxStack+panicFramesMargin)
+	for index, pc := range result.pcs {
+		if frameOf(pc).Function == panicFunction {
+			result.pcs = result.pcs[index+1:]
+			break
 		}
-		result.Items[index] = item
+	}
+	if maxStack < len(result.pcs) {
+		result.pcs = result.pcs[:maxStack]
 	}
 	return result
 }
 
-func (c *callerInfo) setCount(n int) {
-	count := n
-	if len(c.Items) < count {
-		count = len(c.Items)
-	}
-	if count < 0 {
-		count = 0
+// callerWithMax returns at most max frames of the callers.
+// Only the program counters are captured here,
+// the frames are resolved by runtime.CallersFrames on demand,
+// so the inlined functions are included with their own lines.
+func callerWithMax(skip int, max int) *callerInfo {
+	pcs := make([]uintptr, max)
+	n := runtime.Callers(skip+2, pcs)
+	return &callerInfo{
+		pcs: pcs[:n],
 	}
-	c.outputCount = count
 }
@@ -3,7 +3,7 @@ package errors
 import "testing"
 
 func TestCaller(t *testing.T) {
-	info := caller(0).Items[0]
+	info := caller(0).Items()[0]
 	if info.File != "github.com/trimark-jp/errors/caller_test.go" {
 		t.Fatal("can't get caller info file ", info.File)
 	}
@@ -14,3 +14,85 @@ func TestCaller(t *testing.T) {
 		t.Fatal("can't get caller info Line", info.Line)
 	}
 }
+
+func TestCallerInlined(t *testing.T) {
+	items := inlinableCaller().Items()
+	if items[0].Function != "github.com/trimark-jp/errors.inlinableCaller" {
+		t.Fatal("inlined frame is dropped", items[0].Function)
+	}
+	if items[0].Line != 53 {
+		t.Fatal("invalid inlined line", items[0].Line)
+	}
+	if items[1].Function != "github.com/trimark-jp/errors.TestCallerInlined" {
+		t.Fatal("invalid caller of inlined frame", items[1].Function)
+	}
+	if items[1].Line != 19 {
+		t.Fatal("invalid caller line", items[1].Line)
+	}
+
+	items = notInlinedCaller().Items()
+	if items[0].Function != "github.com/trimark-jp/errors.notInlinedCaller" {
+		t.Fatal("invalid frame", items[0].Function)
+	}
+	if items[0].Line != 58 {
+		t.Fatal("invalid line", items[0].Line)
+	}
+}
+
+func TestNewInlined(t *testing.T) {
+	e := inlinableNew().(*leafError)
+	if e.info.Items()[0].Function != "github.com/trimark-jp/errors.inlinableNew" {
+		t.Fatal("invalid caller of New", e.info.Items()[0].Function)
+	}
+	if e.info.Items()[1].Function != "github.com/trimark-jp/errors.TestNewInlined" {
+		t.Fatal("invalid caller of inlined function", e.info.Items()[1].Function)
+	}
+}
+
+func inlinableCaller() *callerInfo {
+	return caller(0)
+}
+
+//go:noinline
+func notInlinedCaller() *callerInfo {
+	return caller(0)
+}
+
+func inlinableNew() error {
+	return New("inlined")
+}
+
+func TestCallerLazy(t *testing.T) {
+	info := caller(0)
+	if info.items != nil {
+		t.Fatal("frames are resolved before use")
+	}
+	if info.String() == "" || info.items == nil {
+		t.Fatal("frames are not resolved", info.String())
+	}
+	if frameOf(info.pcs[0]) != info.Items()[0] {
+		t.Fatal("frame is not cached")
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		New("error")
+	}
+}
+
+func BenchmarkWrap(b *testing.B) {
+	inner := New("inner")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Wrap(inner, "outer")
+	}
+}
+
+func BenchmarkNewAndString(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		StringWithLocation(New("error"))
+	}
+}
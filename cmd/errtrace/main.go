@@ -0,0 +1,252 @@
+// Command errtrace pretty-prints the JSON error traces
+// written by JSON, JSONAll and JSONWithStack of github.com/trimark-jp/errors.
+//
+// Usage:
+//
+//	errtrace [flags] [file ...]
+//
+// The traces are read from the files, or from the standard input if no file is given.
+// Each trace is printed as the indented tree of StringWithInner.
+//
+// Flags:
+//
+//	-color auto|always|never  colour the locations and the messages
+//	-filter app|noruntime      hide the frames of the standard library or the runtime
+//	-include prefixes          print only the frames of the packages, separated by commas
+//	-exclude prefixes          hide the frames of the packages, separated by commas
+//	-frames                    print the frames of each error except the frames in common with the inner
+//	-source                    print only the explicit source of each trace
+//	-jsonl                     read one trace per line, the invalid lines are reported and skipped
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/trimark-jp/errors"
+)
+
+type (
+	// options are the command line flags.
+	options struct {
+		color   string
+		filter  string
+		include string
+		exclude string
+		frames  bool
+		source  bool
+		jsonl   bool
+	}
+
+	// renderer renders the traces by the options.
+	renderer struct {
+		options *options
+		printer *errors.Printer
+		stdout  io.Writer
+		stderr  io.Writer
+		failed  bool
+	}
+)
+
+const (
+	commandName = "errtrace"
+
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+
+	filterApp       = "app"
+	filterNoRuntime = "noruntime"
+
+	// colorLocationFormat prints the location dim and the message bold.
+	colorLocationFormat = "\x1b[2m%s\x1b[0m\t\x1b[1m%s\x1b[0m"
+
+	maxLineSize = 16 * 1024 * 1024
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command and returns the exit status.
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	opts := &options{}
+	flags := flag.NewFlagSet(commandName, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&opts.color, "color", colorAuto, "colour the output: auto, always or never")
+	flags.StringVar(&opts.filter, "filter", "", "hide frames by the preset: app or noruntime")
+	flags.StringVar(&opts.include, "include", "", "print only the frames of the package prefixes separated by commas")
+	flags.StringVar(&opts.exclude, "exclude", "", "hide the frames of the package prefixes separated by commas")
+	flags.BoolVar(&opts.frames, "frames", false, "print the frames except the ones in common with the inner")
+	flags.BoolVar(&opts.source, "source", false, "print only the explicit source")
+	flags.BoolVar(&opts.jsonl, "jsonl", false, "read one trace per line")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	p, err := opts.printer(stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", commandName, err)
+		return 2
+	}
+	r := &renderer{
+		options: opts,
+		printer: p,
+		stdout:  stdout,
+		stderr:  stderr,
+	}
+
+	if flags.NArg() <= 0 {
+		r.render("-", stdin)
+	}
+	for _, name := range flags.Args() {
+		if name == "-" {
+			r.render(name, stdin)
+			continue
+		}
+		f, err := os.Open(name)
+		if err != nil {
+			r.reportf("%v", err)
+			continue
+		}
+		r.render(name, f)
+		f.Close()
+	}
+
+	if r.failed {
+		return 1
+	}
+	return 0
+}
+
+// printer returns the printer by the options.
+func (o *options) printer(stdout io.Writer) (*errors.Printer, error) {
+	p := errors.NewPrinter()
+
+	switch o.color {
+	case colorAlways:
+		p = p.WithLocationFormat(colorLocationFormat)
+	case colorAuto:
+		if isTerminal(stdout) {
+			p = p.WithLocationFormat(colorLocationFormat)
+		}
+	case colorNever:
+	default:
+		return nil, fmt.Errorf("invalid color %q", o.color)
+	}
+
+	switch o.filter {
+	case filterApp:
+		p = p.WithFrameFilter(errors.AppOnlyFrames)
+	case filterNoRuntime:
+		p = p.WithFrameFilter(errors.NoRuntimeFrames)
+	case "":
+	default:
+		return nil, fmt.Errorf("invalid filter %q", o.filter)
+	}
+	if prefixes := splitList(o.include); prefixes != nil {
+		p = p.WithFrameFilter(errors.IncludePackages(prefixes...))
+	}
+	if prefixes := splitList(o.exclude); prefixes != nil {
+		p = p.WithFrameFilter(errors.ExcludePackages(prefixes...))
+	}
+
+	if o.frames {
+		p = p.WithCommonFramesElided(true)
+	}
+	return p, nil
+}
+
+// render renders the traces in the r.
+func (r *renderer) render(name string, in io.Reader) {
+	if r.options.jsonl {
+		r.renderLines(name, in)
+		return
+	}
+
+	dec := json.NewDecoder(in)
+	for index := 1; ; index++ {
+		raw := json.RawMessage{}
+		if err := dec.Decode(&raw); err != nil {
+			if err != io.EOF {
+				r.reportf("%s: trace %d: %v", name, index, err)
+			}
+			return
+		}
+		r.renderTrace(name, index, string(raw))
+	}
+}
+
+// renderLines renders a trace in each line of the in.
+func (r *renderer) renderLines(name string, in io.Reader) {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		r.renderTrace(name, line, text)
+	}
+	if err := scanner.Err(); err != nil {
+		r.reportf("%s: %v", name, err)
+	}
+}
+
+// renderTrace renders the trace s, the index is the line or the order of the trace.
+func (r *renderer) renderTrace(name string, index int, s string) {
+	err, parseErr := errors.ParseJSON(s)
+	if parseErr != nil {
+		r.reportf("%s:%d: %v", name, index, parseErr)
+		return
+	}
+	if err == nil {
+		return
+	}
+
+	if r.options.source {
+		if source := errors.ExplicitSourceOf(err); source != nil {
+			fmt.Fprintln(r.stdout, source.Error())
+		}
+		return
+	}
+	fmt.Fprintln(r.stdout, r.printer.StringWithInner(err))
+}
+
+func (r *renderer) reportf(format string, a ...interface{}) {
+	r.failed = true
+	fmt.Fprintf(r.stderr, commandName+": "+format+"\n", a...)
+}
+
+// splitList returns the non-empty items separated by commas.
+func splitList(s string) []string {
+	var result []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// isTerminal reports whether the w is a terminal.
+// NO_COLOR disables the colour.
+func isTerminal(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
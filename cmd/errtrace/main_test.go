@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/trimark-jp/errors"
+)
+
+func trace(t *testing.T) string {
+	err := errors.Wrap(errors.WrapBySourceMsg(errors.New("inner"), "invalid user"), "outer")
+	s, jsonErr := errors.JSONAll(err)
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	return s
+}
+
+func runWith(t *testing.T, stdin string, args ...string) (string, string, int) {
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	status := run(args, strings.NewReader(stdin), stdout, stderr)
+	return stdout.String(), stderr.String(), status
+}
+
+func TestRun(t *testing.T) {
+	s := trace(t)
+	stdout, stderr, status := runWith(t, s+"\n"+s)
+	if status != 0 || stderr != "" {
+		t.Fatal("failed", status, stderr)
+	}
+	lines := strings.Split(stdout, "\n")
+	if len(lines) != 9 {
+		t.Fatal("invalid lines", lines)
+	}
+	if !strings.HasSuffix(lines[0], "\touter") || !strings.HasPrefix(lines[1], "\t") || !strings.HasSuffix(lines[1], "\tinvalid user") {
+		t.Fatal("invalid tree", lines)
+	}
+	if strings.Contains(stdout, "\x1b[") {
+		t.Fatal("coloured without terminal", stdout)
+	}
+
+	stdout, _, _ = runWith(t, s, "-color", "always")
+	if !strings.Contains(stdout, "\x1b[1mouter\x1b[0m") {
+		t.Fatal("not coloured", stdout)
+	}
+
+	stdout, _, _ = runWith(t, s, "-source")
+	if stdout != "invalid user\n" {
+		t.Fatal("invalid source", stdout)
+	}
+
+	stdout, _, _ = runWith(t, s, "-exclude", "github.com/trimark-jp/errors")
+	if strings.Contains(stdout, "trimark-jp/errors") {
+		t.Fatal("frames not filtered", stdout)
+	}
+
+	if _, stderr, status := runWith(t, s, "-filter", "unknown"); status != 2 || stderr == "" {
+		t.Fatal("invalid filter accepted", status)
+	}
+}
+
+func TestRunJSONL(t *testing.T) {
+	s := trace(t)
+	stdout, stderr, status := runWith(t, s+"\n\nnot json\n"+s+"\n", "-jsonl", "-source")
+	if stdout != "invalid user\ninvalid user\n" {
+		t.Fatal("invalid output", stdout)
+	}
+	if status != 1 || !strings.HasPrefix(stderr, "errtrace: -:3: ") {
+		t.Fatal("invalid line not reported", status, stderr)
+	}
+}
+
+func TestRunFiles(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "trace.json")
+	if err := os.WriteFile(name, []byte(trace(t)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, stderr, status := runWith(t, "", "-source", name, filepath.Join(t.TempDir(), "missing.json"))
+	if stdout != "invalid user\n" || status != 1 || !strings.Contains(stderr, "missing.json") {
+		t.Fatal("invalid output", stdout, stderr, status)
+	}
+	if isTerminal(io.Discard) {
+		t.Fatal("discard is terminal")
+	}
+}
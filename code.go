@@ -0,0 +1,157 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+type (
+	// Code identifies a kind of errors.
+	// Unlike the Go type of the source error, the code is kept in JSON
+	// across process boundaries.
+	Code string
+
+	// CodeInfo describes a code registered by RegisterCode.
+	CodeInfo struct {
+		Code        Code
+		Message     string
+		HTTPStatus  int
+		Description string
+	}
+)
+
+var (
+	codeRegistryMutex sync.RWMutex
+	codeRegistry      = map[Code]*CodeInfo{}
+)
+
+// RegisterCode registers the code with the default message, the HTTP status and the description.
+// It returns the code, so the code can be declared once as a variable.
+// It panics if the code is empty or already registered.
+//
+//	var CodeUserNotFound = errors.RegisterCode(errors.CodeInfo{
+//		Code:       "user_not_found",
+//		Message:    "user not found",
+//		HTTPStatus: http.StatusNotFound,
+//	})
+func RegisterCode(info CodeInfo) Code {
+	if info.Code == "" {
+		panic("errors: empty code is registered")
+	}
+
+	codeRegistryMutex.Lock()
+	defer codeRegistryMutex.Unlock()
+	if _, ok := codeRegistry[info.Code]; ok {
+		panic(fmt.Sprintf("errors: code %q is already registered", info.Code))
+	}
+	registered := info
+	codeRegistry[info.Code] = &registered
+	return info.Code
+}
+
+// LookupCode returns the registered info of the code.
+func LookupCode(code Code) (CodeInfo, bool) {
+	codeRegistryMutex.RLock()
+	defer codeRegistryMutex.RUnlock()
+	info, ok := codeRegistry[code]
+	if !ok {
+		return CodeInfo{}, false
+	}
+	return *info, true
+}
+
+// Codes returns the registered codes sorted by the code.
+func Codes() []CodeInfo {
+	codeRegistryMutex.RLock()
+	defer codeRegistryMutex.RUnlock()
+	result := make([]CodeInfo, 0, len(codeRegistry))
+	for _, info := range codeRegistry {
+		result = append(result, *info)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Code < result[j].Code
+	})
+	return result
+}
+
+// NewCode returns a new error which has the code.
+// If the msg is empty, the registered message of the code is used.
+func NewCode(code Code, msg string) error {
+	e := newErrorType(nil, codeMessage(code, msg), 1)
+	e.code = code
+	return e.typed()
+}
+
+// NewCodef returns a new error which has the code.
+func NewCodef(code Code, format string, a ...interface{}) error {
+	e := newErrorType(nil, fmt.Sprintf(format, a...), 1)
+	e.code = code
+	return e.typed()
+}
+
+// WrapByCode returns the err by new error which has the code and msg.
+// If the msg is empty, the registered message of the code is used.
+func WrapByCode(err error, code Code, msg string) error {
+	if err == nil {
+		return nil
+	}
+	e := newErrorType(err, codeMessage(code, msg), 1)
+	e.code = code
+	return e
+}
+
+// CodeOf returns the code of the err.
+// Like ExplicitSourceOf, the code of the outer error wins,
+// and the errors in a collection are searched in order.
+// Returns "" if no code is found.
+func CodeOf(err error) Code {
+	if e, ok := err.(*errorSource); ok {
+		if e.code != "" {
+			return e.code
+		}
+		if code := CodeOf(e.source); code != "" {
+			return code
+		}
+		return CodeOf(e.inner)
+	}
+	if e, ok := err.(*collection); ok {
+		for _, inner := range e.errs {
+			if code := CodeOf(inner); code != "" {
+				return code
+			}
+		}
+		return ""
+	}
+	if e, ok := errorTypeOf(err); ok {
+		if e.code != "" {
+			return e.code
+		}
+		return CodeOf(e.inner)
+	}
+	for _, inner := range unwrapForeign(err) {
+		if code := CodeOf(inner); code != "" {
+			return code
+		}
+	}
+	return ""
+}
+
+// CodeInfoOf returns the registered info of the code of the err.
+func CodeInfoOf(err error) (CodeInfo, bool) {
+	code := CodeOf(err)
+	if code == "" {
+		return CodeInfo{}, false
+	}
+	return LookupCode(code)
+}
+
+func codeMessage(code Code, msg string) string {
+	if msg != "" {
+		return msg
+	}
+	if info, ok := LookupCode(code); ok && info.Message != "" {
+		return info.Message
+	}
+	return string(code)
+}
@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+var (
+	codeTestNotFound = RegisterCode(CodeInfo{
+		Code:        "test_not_found",
+		Message:     "not found",
+		HTTPStatus:  http.StatusNotFound,
+		Description: "the resource is not found",
+	})
+	codeTestInvalid = RegisterCode(CodeInfo{
+		Code:       "test_invalid",
+		Message:    "invalid",
+		HTTPStatus: http.StatusBadRequest,
+	})
+)
+
+func TestCode(t *testing.T) {
+	e := NewCode(codeTestNotFound, "")
+	if e.Error() != "not found" {
+		t.Fatal("invalid default message", e)
+	}
+	if NewCode("unregistered", "").Error() != "unregistered" {
+		t.Fatal("invalid message of unregistered code")
+	}
+
+	outer := Wrap(Wrap(e, "middle"), "outer")
+	if CodeOf(outer) != codeTestNotFound {
+		t.Fatal("invalid code", CodeOf(outer))
+	}
+	info, ok := CodeInfoOf(outer)
+	if !ok || info.HTTPStatus != http.StatusNotFound {
+		t.Fatal("invalid code info", info)
+	}
+
+	overridden := WrapByCode(outer, codeTestInvalid, "")
+	if CodeOf(overridden) != codeTestInvalid {
+		t.Fatal("outer code not preferred", CodeOf(overridden))
+	}
+
+	c := Merge(Wrap(io.EOF, "no code"), overridden)
+	if CodeOf(c) != codeTestInvalid {
+		t.Fatal("invalid code of collection", CodeOf(c))
+	}
+	if CodeOf(io.EOF) != "" || CodeOf(nil) != "" {
+		t.Fatal("code of foreign error")
+	}
+}
+
+func TestCodeJSON(t *testing.T) {
+	e := Wrap(NewCode(codeTestNotFound, "user not found"), "outer")
+	s, _ := JSONAll(e)
+	if !strings.Contains(s, `"code":"test_not_found"`) {
+		t.Fatal("code not marshalled", s)
+	}
+
+	parsed, _ := ParseJSON(s)
+	if CodeOf(parsed) != codeTestNotFound {
+		t.Fatal("code not parsed", CodeOf(parsed))
+	}
+}
+
+func TestRegisterCode(t *testing.T) {
+	if _, ok := LookupCode(codeTestInvalid); !ok {
+		t.Fatal("code not registered")
+	}
+	found := false
+	for _, info := range Codes() {
+		if info.Code == codeTestNotFound {
+			found = info.Description == "the resource is not found"
+		}
+	}
+	if !found {
+		t.Fatal("code not listed", Codes())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("duplicated code registered")
+		}
+	}()
+	RegisterCode(CodeInfo{Code: codeTestNotFound})
+}
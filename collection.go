@@ -2,7 +2,6 @@ package errors
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"strings"
 )
@@ -10,8 +9,7 @@ import (
 type (
 	// collection has a list of errors.
 	collection struct {
-		errs        []error
-		callerCount int
+		errs []error
 	}
 )
 
@@ -35,7 +33,15 @@ func (c *collection) Error() string {
 
 }
 
+// Unwrap returns the collected errors.
+// It makes errors.Is and errors.As of the standard library
+// walk through every collected error.
+func (c *collection) Unwrap() []error {
+	return c.errs
+}
+
 // Merge returns an error which has l and r.
+// Merge never modifies l and r. It returns a new collection on each call.
 func Merge(l error, r error) error {
 	if l == nil {
 		return r
@@ -44,14 +50,6 @@ func Merge(l error, r error) error {
 		return l
 	}
 
-	if c, ok := l.(*collection); ok {
-		c.append(r)
-		return c
-	}
-	if c, ok := r.(*collection); ok {
-		c.insertFront(l)
-		return c
-	}
 	c := newCollection()
 	c.append(l)
 	c.append(r)
@@ -59,20 +57,10 @@ func Merge(l error, r error) error {
 }
 
 // MarshalJSON implements json.Marshaler interface.
+// It marshals the errors with all the stack by the default printer.
 func (c *collection) MarshalJSON() ([]byte, error) {
-	obj := struct {
-		Errs []*errMarshal `json:"errors"`
-	}{
-		Errs: make([]*errMarshal, len(c.errs)),
-	}
-	for index, e := range c.errs {
-		em := &errMarshal{
-			err:         e,
-			callerCount: c.callerCount,
-		}
-		obj.Errs[index] = em
-	}
-	return json.Marshal(&obj)
+	p := DefaultPrinter()
+	return newErrMarshal(p, c, p.stackCount()).MarshalJSON()
 }
 
 // newCollection returns a new collection.
@@ -83,6 +71,8 @@ func newCollection() *collection {
 }
 
 // append appends the error to the collection.
+// The errors of the other collection are copied,
+// so the collections never share their backing arrays.
 func (c *collection) append(err error) {
 	if tail, ok := err.(*collection); ok {
 		c.errs = append(c.errs, tail.errs...)
@@ -91,14 +81,6 @@ func (c *collection) append(err error) {
 	c.errs = append(c.errs, err)
 }
 
-func (c *collection) insertFront(err error) {
-	if head, ok := err.(*collection); ok {
-		c.errs = append(head.errs, c.errs...)
-		return
-	}
-	c.errs = append([]error{err}, c.errs...)
-}
-
 func (c *collection) source() error {
 	if len(c.errs) <= 0 {
 		return nil
@@ -121,38 +103,34 @@ func (c *collection) explicitSource() error {
 	return nil
 }
 
-func (c *collection) setCallerCount(n int) {
-	for _, e := range c.errs {
-		if m, ok := e.(callerMarshal); ok {
-			m.setCallerCount(n)
-		}
-	}
-	c.callerCount = n
+// StringWithLocation returns the locations and the messages by the default printer.
+func (c *collection) StringWithLocation() string {
+	return c.stringWithLocation(DefaultPrinter())
 }
 
-func (c *collection) StringWithLocation() string {
+func (c *collection) stringWithLocation(p *Printer) string {
 	if len(c.errs) <= 0 {
 		return ""
 	}
 
 	msgs := make([]string, len(c.errs))
 	for index, e := range c.errs {
-		msgs[index] = StringWithLocation(e)
+		msgs[index] = p.StringWithLocation(e)
 	}
 	return fmt.Sprintf(collectionStringFormat,
 		strings.Join(msgs, collectionSeparator))
 }
 
-func (c *collection) stringWithInner(indent string) string {
+func (c *collection) stringWithInner(p *Printer, indent string) string {
 	buf := &bytes.Buffer{}
 
 	fmt.Fprintln(buf, indent+"[")
-	innerIndent := indent + StringWithInnerIndent
+	innerIndent := indent + p.indent
 	for index, e := range c.errs {
 		if 0 < index {
 			fmt.Fprintln(buf, innerIndent+",")
 		}
-		fmt.Fprint(buf, stringWithInner(e, innerIndent))
+		fmt.Fprint(buf, p.stringWithInner(e, innerIndent))
 	}
 	fmt.Fprintln(buf, indent+"]")
 
@@ -2,9 +2,12 @@ package errors
 
 import (
 	"encoding/json"
+	stderrors "errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -54,3 +57,79 @@ func TestSourceOfCollection(t *testing.T) {
 	b, _ := json.Marshal(e)
 	t.Log(string(b))
 }
+
+func TestUnwrapCollection(t *testing.T) {
+	source := &httperror{
+		StatusCode: http.StatusNotFound,
+		Message:    errMessageURLNotFound,
+	}
+	c := Merge(Wrap(io.EOF, "left"), AsSource(source))
+
+	if !stderrors.Is(c, io.EOF) {
+		t.Fatal("errors.Is can't find left", c)
+	}
+	var herr *httperror
+	if !stderrors.As(c, &herr) || herr != source {
+		t.Fatal("errors.As can't find right", herr)
+	}
+	if stderrors.Is(c, io.ErrClosedPipe) {
+		t.Fatal("errors.Is found unrelated error", c)
+	}
+}
+
+func TestMergeImmutable(t *testing.T) {
+	left := Merge(New("left 1"), New("left 2"))
+	right := Merge(New("right 1"), New("right 2"))
+	leftMessage := left.Error()
+	rightMessage := right.Error()
+
+	m := Merge(left, right)
+	Merge(New("front"), left)
+	Merge(right, New("back"))
+
+	if left.Error() != leftMessage {
+		t.Fatal("left is modified", left)
+	}
+	if right.Error() != rightMessage {
+		t.Fatal("right is modified", right)
+	}
+	if len(m.(*collection).errs) != 4 {
+		t.Fatal("invalid merged errors", m)
+	}
+	if m == left || m == right {
+		t.Fatal("merge returned an argument", m)
+	}
+}
+
+func TestMergeConcurrently(t *testing.T) {
+	const (
+		count = 100
+	)
+	base := Merge(New("base 1"), New("base 2"))
+	baseMessage := base.Error()
+
+	wg := &sync.WaitGroup{}
+	results := make([]error, count)
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			if index%2 == 0 {
+				results[index] = Merge(base, Newf("right %d", index))
+			} else {
+				results[index] = Merge(Newf("left %d", index), base)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if base.Error() != baseMessage {
+		t.Fatal("base is modified", base)
+	}
+	for index, result := range results {
+		c := result.(*collection)
+		if len(c.errs) != 3 {
+			t.Fatal("invalid merged errors", index, result)
+		}
+	}
+}
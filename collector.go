@@ -0,0 +1,55 @@
+package errors
+
+import "sync"
+
+type (
+	// Collector collects errors.
+	// It is safe for concurrent use by multiple goroutines.
+	// The zero value is an empty Collector ready to use.
+	Collector struct {
+		mutex sync.Mutex
+		errs  []error
+	}
+)
+
+// Add adds the err to the collector with the caller of Add.
+// Add does nothing if the err is nil.
+func (c *Collector) Add(err error) {
+	if err == nil {
+		return
+	}
+	e := newLocationOnly(err, 1)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.errs = append(c.errs, e)
+}
+
+// Len returns the count of the collected errors.
+func (c *Collector) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.errs)
+}
+
+// Err returns an error which has the collected errors.
+// Returns nil if no error is collected.
+// The returned error is not changed by later Add and Reset.
+func (c *Collector) Err() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	if len(c.errs) <= 0 {
+		return nil
+	}
+
+	result := newCollection()
+	result.errs = append(result.errs, c.errs...)
+	return result
+}
+
+// Reset removes the collected errors.
+func (c *Collector) Reset() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.errs = nil
+}
@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCollector(t *testing.T) {
+	c := &Collector{}
+	if c.Err() != nil {
+		t.Fatal("empty collector returned error", c.Err())
+	}
+
+	c.Add(nil)
+	c.Add(io.EOF)
+	c.Add(New("second"))
+	if c.Len() != 2 {
+		t.Fatal("invalid length", c.Len())
+	}
+
+	err := c.Err()
+	expected := fmt.Sprintf(collectionStringFormat,
+		strings.Join([]string{io.EOF.Error(), "second"}, collectionSeparator))
+	if err.Error() != expected {
+		t.Fatal("invalid message", err)
+	}
+
+	s := StringWithLocation(err)
+	if !strings.Contains(s, "github.com/trimark-jp/errors/collector_test.go:18:github.com/trimark-jp/errors.TestCollector\t"+io.EOF.Error()) {
+		t.Fatal("add location not found", s)
+	}
+
+	c.Reset()
+	if c.Len() != 0 || c.Err() != nil {
+		t.Fatal("collector not reset", c.Len())
+	}
+	if err.Error() != expected {
+		t.Fatal("returned error is modified", err)
+	}
+}
+
+func TestCollectorConcurrently(t *testing.T) {
+	const (
+		count = 100
+	)
+	c := &Collector{}
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			c.Add(Newf("error %d", index))
+			c.Err()
+		}(i)
+	}
+	wg.Wait()
+
+	if c.Len() != count {
+		t.Fatal("invalid length", c.Len())
+	}
+}
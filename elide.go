@@ -0,0 +1,95 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+const (
+	elidedFramesFormat = "\t... %d frames in common with inner"
+	frameFormat        = "\tat " + locationFormat
+)
+
+// WithCommonFramesElided returns a new Printer which prints only the frames
+// of each error which differ from the frames of the inner error.
+// The frames in common with the inner error are counted as elidedFrames in JSON,
+// and StringWithInner prints the remaining frames of each error
+// with "... N frames in common with inner".
+func (p *Printer) WithCommonFramesElided(elide bool) *Printer {
+	result := p.clone()
+	result.elideCommon = elide
+	return result
+}
+
+// layerFrames returns at most n frames of the info and the count of the elided frames.
+// The frames and the elided frames add up to the first n frames of the info.
+// The frames in common with the inner error are elided if the p elides them.
+func (p *Printer) layerFrames(info *callerInfo, inner error, n int) ([]*callerInfoItem, int) {
+	innerInfo := callerInfoOf(inner)
+	if !p.elideCommon || info == nil || innerInfo == nil {
+		return p.frames(info, n), 0
+	}
+
+	all := p.frames(info, n)
+	innerFrames := p.frames(innerInfo, len(innerInfo.Items()))
+	kept := commonFramesIndex(all, innerFrames)
+	if kept <= 0 && 0 < len(all) {
+		// the location of the error is always kept.
+		kept = 1
+	}
+	return all[:kept], len(all) - kept
+}
+
+// callerInfoOf returns the caller info of the err, or nil for the other errors.
+func callerInfoOf(err error) *callerInfo {
+	if e, ok := errorTypeOf(err); ok {
+		return e.info
+	}
+	if e, ok := err.(*errorSource); ok {
+		return e.info
+	}
+	return nil
+}
+
+// commonFramesIndex returns the index of the first frame of the frames
+// from which all the frames are in common with the inner frames.
+// The stacks may be cut at different depths,
+// so the common frames are matched until either of them ends.
+func commonFramesIndex(frames []*callerInfoItem, inner []*callerInfoItem) int {
+	for index := range frames {
+		for innerIndex := range inner {
+			if sameFrames(frames[index:], inner[innerIndex:]) {
+				return index
+			}
+		}
+	}
+	return len(frames)
+}
+
+func sameFrames(frames []*callerInfoItem, inner []*callerInfoItem) bool {
+	for index := 0; index < len(frames) && index < len(inner); index++ {
+		if !sameFrame(frames[index], inner[index]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameFrame(a *callerInfoItem, b *callerInfoItem) bool {
+	return a.Function == b.Function && a.File == b.File && a.Line == b.Line
+}
+
+// writeLayerFrames writes the frames of the error except the first one in the header,
+// and the count of the elided frames.
+func (p *Printer) writeLayerFrames(w io.Writer, info *callerInfo, inner error, indent string) {
+	if !p.elideCommon {
+		return
+	}
+	frames, elided := p.layerFrames(info, inner, p.stackCount())
+	for index := 1; index < len(frames); index++ {
+		fmt.Fprintln(w, indent+fmt.Sprintf(frameFormat, frames[index].File, frames[index].Line, frames[index].Function))
+	}
+	if 0 < elided {
+		fmt.Fprintln(w, indent+fmt.Sprintf(elidedFramesFormat, elided))
+	}
+}
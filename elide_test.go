@@ -0,0 +1,93 @@
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func elideInner() error {
+	return New("inner")
+}
+
+func elideOuter() error {
+	return Wrap(elideInner(), "outer")
+}
+
+func TestCommonFramesElided(t *testing.T) {
+	e := Wrap(elideOuter(), "top")
+	p := NewPrinter().WithMaxStack(20).WithCommonFramesElided(true)
+
+	s, _ := p.JSONAll(e)
+	type layer struct {
+		Callers      []*callerInfoItem `json:"callers"`
+		ElidedFrames int               `json:"elidedFrames"`
+	}
+	obj := struct {
+		layer
+		Inner struct {
+			layer
+			Inner layer `json:"inner"`
+		} `json:"inner"`
+	}{}
+	json.Unmarshal([]byte(s), &obj)
+
+	if len(obj.Callers) != 1 || obj.Callers[0].Function != "github.com/trimark-jp/errors.TestCommonFramesElided" || obj.ElidedFrames == 0 {
+		t.Fatal("invalid top frames", s)
+	}
+	if len(obj.Inner.Callers) != 1 || obj.Inner.Callers[0].Function != "github.com/trimark-jp/errors.elideOuter" {
+		t.Fatal("invalid outer frames", s)
+	}
+	if obj.Inner.ElidedFrames != obj.ElidedFrames+1 {
+		t.Fatal("invalid elided frames", s)
+	}
+	if 3 > len(obj.Inner.Inner.Callers) || obj.Inner.Inner.ElidedFrames != 0 {
+		t.Fatal("invalid inner frames", s)
+	}
+
+	text := p.StringWithInner(e)
+	if !strings.Contains(text, "\t... ") || !strings.Contains(text, " frames in common with inner") {
+		t.Fatal("elided frames not printed", text)
+	}
+	if !strings.Contains(text, "\tat ") {
+		t.Fatal("frames of the inner not printed", text)
+	}
+
+	s, _ = NewPrinter().WithMaxStack(20).JSONAll(e)
+	if strings.Contains(s, "elidedFrames") {
+		t.Fatal("frames elided by default", s)
+	}
+}
+
+func TestCommonFramesIndex(t *testing.T) {
+	frame := func(function string, line int) *callerInfoItem {
+		return &callerInfoItem{Function: function, Line: line}
+	}
+	outer := []*callerInfoItem{frame("a", 2), frame("main", 1), frame("runtime.main", 1)}
+	inner := []*callerInfoItem{frame("b", 1), frame("a", 1), frame("main", 1)}
+
+	if index := commonFramesIndex(outer, inner); index != 1 {
+		t.Fatal("invalid index of the cut stacks", index)
+	}
+	if index := commonFramesIndex(outer, nil); index != len(outer) {
+		t.Fatal("invalid index without inner", index)
+	}
+}
+
+func TestCommonFramesElidedWithStack(t *testing.T) {
+	e := Wrap(elideOuter(), "top")
+	p := NewPrinter().WithCommonFramesElided(true)
+	all := len(p.frames(e.(*errorType).info, 20))
+
+	for n := 1; n <= all; n++ {
+		s, _ := p.JSONWithStack(e, n)
+		obj := struct {
+			Callers      []*callerInfoItem `json:"callers"`
+			ElidedFrames int               `json:"elidedFrames"`
+		}{}
+		json.Unmarshal([]byte(s), &obj)
+		if len(obj.Callers)+obj.ElidedFrames != n {
+			t.Fatal("frames cut by the stack count are lost", n, s)
+		}
+	}
+}
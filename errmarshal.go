@@ -1,38 +1,250 @@
 package errors
 
-import "encoding/json"
+import (
+	"encoding/json"
+	stderrors "errors"
+)
 
 type (
+	// errMarshal marshals the err by the printer with stackCount frames.
 	errMarshal struct {
-		err         error
-		callerCount int
+		err        error
+		printer    *Printer
+		stackCount int
 	}
 
-	callerMarshal interface {
-		json.Marshaler
-		setCallerCount(int)
+	// errUnmarshal is the union of the JSON objects of the errors.
+	errUnmarshal struct {
+		Inner        json.RawMessage        `json:"inner"`
+		Callers      json.RawMessage        `json:"callers"`
+		Message      string                 `json:"message"`
+		IsSource     bool                   `json:"isSource"`
+		Fields       map[string]interface{} `json:"fields"`
+		Code         Code                   `json:"code"`
+		Public       string                 `json:"public"`
+		LocationOnly bool                   `json:"locationOnly"`
+		Errors       []json.RawMessage      `json:"errors"`
+		GoType       string                 `json:"goType"`
 	}
 )
 
-func (e *errMarshal) setCallerCount(n int) {
-	if m, ok := e.err.(callerMarshal); ok {
-		m.setCallerCount(n)
-	} else {
+func newErrMarshal(p *Printer, err error, stackCount int) *errMarshal {
+	return &errMarshal{
+		err:        err,
+		printer:    p,
+		stackCount: stackCount,
 	}
-	e.callerCount = n
 }
 
+// MarshalJSON implements json.Marshaler interface.
 func (e *errMarshal) MarshalJSON() ([]byte, error) {
-	if m, ok := e.err.(json.Marshaler); ok {
-		return m.MarshalJSON()
-	}
 	if e.err == nil {
 		return json.Marshal(nil)
 	}
+
+	if s, ok := e.err.(*errorSource); ok {
+		callers, elided := e.printer.layerFrames(s.info, s.inner, e.stackCount)
+		obj := struct {
+			Inner        *errMarshal            `json:"inner"`
+			Callers      []*callerInfoItem      `json:"callers"`
+			ElidedFrames int                    `json:"elidedFrames,omitempty"`
+			Message      string                 `json:"message"`
+			IsSource     bool                   `json:"isSource"`
+			Fields       map[string]interface{} `json:"fields,omitempty"`
+			Code         Code                   `json:"code,omitempty"`
+			Public       string                 `json:"public,omitempty"`
+		}{
+			Inner:        e.marshalerOf(s.inner),
+			Callers:      callers,
+			ElidedFrames: elided,
+			Message:      e.printer.redactMessage(s.Error()),
+			IsSource:     true,
+			Fields:       e.printer.redactFields(s.fields),
+			Code:         s.code,
+			Public:       s.public,
+		}
+		return json.Marshal(&obj)
+	}
+	if t, ok := errorTypeOf(e.err); ok {
+		callers, elided := e.printer.layerFrames(t.info, t.inner, e.stackCount)
+		obj := struct {
+			Inner        *errMarshal            `json:"inner"`
+			Callers      []*callerInfoItem      `json:"callers"`
+			ElidedFrames int                    `json:"elidedFrames,omitempty"`
+			Message      string                 `json:"message"`
+			LocationOnly bool                   `json:"locationOnly,omitempty"`
+			Fields       map[string]interface{} `json:"fields,omitempty"`
+			Code         Code                   `json:"code,omitempty"`
+		}{
+			Inner:        e.marshalerOf(t.inner),
+			Callers:      callers,
+			ElidedFrames: elided,
+			Message:      e.printer.redactMessage(t.msg),
+			LocationOnly: t.locationOnly,
+			Fields:       e.printer.redactFields(t.fields),
+			Code:         t.code,
+		}
+		return json.Marshal(&obj)
+	}
+	if c, ok := e.err.(*collection); ok {
+		obj := struct {
+			Errs []*errMarshal `json:"errors"`
+		}{
+			Errs: make([]*errMarshal, len(c.errs)),
+		}
+		for index, err := range c.errs {
+			obj.Errs[index] = e.marshalerOf(err)
+		}
+		return json.Marshal(&obj)
+	}
+
+	if m, ok := e.err.(json.Marshaler); ok && !e.printer.redacts() {
+		return m.MarshalJSON()
+	}
+	if inners := unwrapForeign(e.err); inners != nil {
+		return e.marshalForeignWrapper(inners)
+	}
+	obj := struct {
+		Callers []*callerInfoItem `json:"callers,omitempty"`
+		Message string            `json:"message"`
+	}{
+		Callers: e.printer.frames(foreignStackOf(e.err), e.stackCount),
+		Message: e.printer.redactMessage(e.err.Error()),
+	}
+	return json.Marshal(&obj)
+}
+
+// marshalForeignWrapper marshals the foreign error which wraps the inners
+// as a plain layer with the message, the Go type and the callers of the StackTrace method.
+// The inner is used for Unwrap() error and the errors for Unwrap() []error.
+func (e *errMarshal) marshalForeignWrapper(inners []error) ([]byte, error) {
 	obj := struct {
-		Message string `json:"message"`
+		Inner   *errMarshal       `json:"inner,omitempty"`
+		Errs    []*errMarshal     `json:"errors,omitempty"`
+		Callers []*callerInfoItem `json:"callers,omitempty"`
+		Message string            `json:"message"`
+		GoType  string            `json:"goType"`
 	}{
-		Message: e.err.Error(),
+		Callers: e.printer.frames(foreignStackOf(e.err), e.stackCount),
+		Message: e.printer.redactMessage(e.err.Error()),
+		GoType:  typeNameOf(e.err),
+	}
+	if unwrapsSingle(e.err) {
+		obj.Inner = e.marshalerOf(inners[0])
+	} else {
+		obj.Errs = make([]*errMarshal, len(inners))
+		for index, inner := range inners {
+			obj.Errs[index] = e.marshalerOf(inner)
+		}
 	}
 	return json.Marshal(&obj)
 }
+
+func (e *errMarshal) marshalerOf(err error) *errMarshal {
+	return newErrMarshal(e.printer, err, e.stackCount)
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+// It rebuilds the error from the output of MarshalJSON.
+func (e *errMarshal) UnmarshalJSON(b []byte) error {
+	err, parseErr := unmarshalError(b)
+	if parseErr != nil {
+		return parseErr
+	}
+	e.err = err
+	return nil
+}
+
+func unmarshalError(b []byte) (error, error) {
+	if isJSONNull(b) {
+		return nil, nil
+	}
+
+	obj := &errUnmarshal{}
+	if err := json.Unmarshal(b, obj); err != nil {
+		return nil, err
+	}
+
+	if obj.GoType != "" {
+		return unmarshalForeignWrapper(obj)
+	}
+
+	if obj.Errors != nil {
+		c := newCollection()
+		for _, raw := range obj.Errors {
+			err, parseErr := unmarshalError(raw)
+			if parseErr != nil {
+				return nil, parseErr
+			}
+			if err != nil {
+				c.append(err)
+			}
+		}
+		return c, nil
+	}
+
+	if obj.Callers == nil {
+		return stderrors.New(obj.Message), nil
+	}
+
+	inner, parseErr := unmarshalError(obj.Inner)
+	if parseErr != nil {
+		return nil, parseErr
+	}
+	info := &callerInfo{}
+	if err := json.Unmarshal(obj.Callers, info); err != nil {
+		return nil, err
+	}
+
+	e := &errorType{
+		inner:        inner,
+		msg:          obj.Message,
+		info:         info,
+		fields:       obj.Fields,
+		code:         obj.Code,
+		locationOnly: obj.LocationOnly,
+	}
+	if obj.IsSource {
+		e.msg = ""
+		return &errorSource{
+			errorType: e,
+			source:    stderrors.New(obj.Message),
+			public:    obj.Public,
+		}, nil
+	}
+	return e.typed(), nil
+}
+
+func isJSONNull(b []byte) bool {
+	return len(b) == 0 || string(b) == "null"
+}
+
+// unmarshalForeignWrapper rebuilds the foreign wrapper error as a parsedWrapper.
+func unmarshalForeignWrapper(obj *errUnmarshal) (error, error) {
+	w := &parsedWrapper{
+		msg:      obj.Message,
+		typeName: obj.GoType,
+		single:   !isJSONNull(obj.Inner),
+	}
+	if obj.Callers != nil {
+		w.info = &callerInfo{}
+		if err := json.Unmarshal(obj.Callers, w.info); err != nil {
+			return nil, err
+		}
+	}
+
+	raws := obj.Errors
+	if w.single {
+		raws = []json.RawMessage{obj.Inner}
+	}
+	for _, raw := range raws {
+		err, parseErr := unmarshalError(raw)
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		if err != nil {
+			w.errs = append(w.errs, err)
+		}
+	}
+	return w, nil
+}
@@ -3,6 +3,7 @@ package errors
 import (
 	"encoding/json"
 	"errors"
+	"io"
 	"testing"
 )
 
@@ -31,3 +32,58 @@ func TestMarshalNormalErr(t *testing.T) {
 		t.Fatal("marshalled nil wrong", s)
 	}
 }
+
+func TestUnmarshal(t *testing.T) {
+	const (
+		rightMiddleMessage = "right middle"
+	)
+	left := Wrap(Wrap(io.ErrClosedPipe, "left inner"), "left outer")
+	right := Wrap(WrapBySourceMsg(New("right inner"), rightMiddleMessage), "right outer")
+	e := Wrap(Merge(left, right), "most out")
+
+	s, err := JSONAll(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseJSON(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s2, err := JSONAll(parsed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != s2 {
+		t.Fatal("json not round-tripped", s, s2)
+	}
+	if StringWithInner(e) != StringWithInner(parsed) {
+		t.Fatal("invalid string with inner", StringWithInner(parsed))
+	}
+
+	source := SourceOf(parsed)
+	if source.Error() != rightMiddleMessage {
+		t.Fatal("invalid source", source)
+	}
+
+	merged := Merge(parsed, New("new"))
+	if _, ok := merged.(*collection); !ok {
+		t.Fatal("can't merge parsed error", merged)
+	}
+}
+
+func TestUnmarshalNil(t *testing.T) {
+	for _, s := range []string{"", "null"} {
+		e, err := ParseJSON(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e != nil {
+			t.Fatal("parsed nil wrong", e)
+		}
+	}
+
+	if _, err := ParseJSON("{"); err == nil {
+		t.Fatal("parsed invalid json")
+	}
+}
@@ -1,21 +1,34 @@
 package errors
 
 import (
-	"encoding/json"
 	"fmt"
 )
 
 type (
 	errorType struct {
-		inner       error
-		msg         string
-		info        *callerInfo
-		callerCount int
+		inner  error
+		msg    string
+		info   *callerInfo
+		fields map[string]interface{}
+		code   Code
+
+		// locationOnly is true for the errors which add only the location
+		// or the fields to the inner error, such as Collector.Add and With.
+		// Their message is the message of the inner error.
+		locationOnly bool
 	}
+
+	// leafError is an errorType without the inner error.
+	// It has the methods of errorType except Cause,
+	// so Cause of github.com/pkg/errors stops at it.
+	leafError errorType
 )
 
 // Error implements error interface.
 func (e *errorType) Error() string {
+	if e.locationOnly && e.inner != nil {
+		return e.inner.Error()
+	}
 	return e.msg
 }
 
@@ -45,35 +58,67 @@ func Wrapf(err error, format string, a ...interface{}) error {
 	return new(err, fmt.Sprintf(format, a...), 1)
 }
 
+// Unwrap returns the inner error.
+// It makes errors.Is and errors.As of the standard library
+// walk through the inner errors.
+func (e *errorType) Unwrap() error {
+	return e.inner
+}
+
 // MarshalJSON implements json.Marshaler interface.
+// It marshals the error with all the stack by the default printer.
 func (e *errorType) MarshalJSON() ([]byte, error) {
-	obj := struct {
-		Inner   *errMarshal `json:"inner"`
-		Callers *callerInfo `json:"callers"`
-		Message string      `json:"message"`
-	}{
-		Inner: &errMarshal{
-			err:         e.inner,
-			callerCount: e.callerCount,
-		},
-		Callers: e.info,
-		Message: e.msg,
-	}
-	return json.Marshal(&obj)
+	p := DefaultPrinter()
+	return newErrMarshal(p, e, p.stackCount()).MarshalJSON()
 }
 
-func (e *errorType) setCallerCount(n int) {
-	if m, ok := e.inner.(callerMarshal); ok {
-		m.setCallerCount(n)
-	}
-	e.callerCount = n
-	e.info.setCount(n)
+// Error implements error interface.
+func (l *leafError) Error() string {
+	return (*errorType)(l).Error()
+}
+
+// Unwrap returns nil, the leaf error has no inner error.
+func (l *leafError) Unwrap() error {
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (l *leafError) MarshalJSON() ([]byte, error) {
+	return (*errorType)(l).MarshalJSON()
 }
 
 func new(inner error, msg string, skip int) error {
+	return newErrorType(inner, msg, skip+1).typed()
+}
+
+// newLocationOnly returns a new error which adds only the location to the inner.
+func newLocationOnly(inner error, skip int) *errorType {
+	e := newErrorType(inner, "", skip+1)
+	e.locationOnly = true
+	return e
+}
+
+func newErrorType(inner error, msg string, skip int) *errorType {
 	return &errorType{
 		inner: inner,
 		msg:   msg,
 		info:  caller(skip + 1),
 	}
 }
+
+// typed returns the e as leafError if the e has no inner error.
+func (e *errorType) typed() error {
+	if e.inner == nil {
+		return (*leafError)(e)
+	}
+	return e
+}
+
+// errorTypeOf returns the errorType of the err which is errorType or leafError.
+func errorTypeOf(err error) (*errorType, bool) {
+	if l, ok := err.(*leafError); ok {
+		return (*errorType)(l), true
+	}
+	e, ok := err.(*errorType)
+	return e, ok
+}
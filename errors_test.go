@@ -2,6 +2,9 @@ package errors
 
 import (
 	"encoding/json"
+	stderrors "errors"
+	"io"
+	"os"
 	"testing"
 )
 
@@ -30,6 +33,29 @@ func TestWrap(t *testing.T) {
 	}
 }
 
+func TestWrapEmptyMessage(t *testing.T) {
+	e := Wrap(io.EOF, "")
+	if e.Error() != "" {
+		t.Fatal("invalid message", e.Error())
+	}
+
+	s, _ := JSONAll(e)
+	parsed, _ := ParseJSON(s)
+	if parsed.Error() != "" {
+		t.Fatal("invalid parsed message", parsed.Error())
+	}
+
+	w := With(io.EOF, "userID", 10)
+	if w.Error() != io.EOF.Error() {
+		t.Fatal("invalid message of With", w.Error())
+	}
+	s, _ = JSONAll(w)
+	parsed, _ = ParseJSON(s)
+	if parsed.Error() != io.EOF.Error() {
+		t.Fatal("invalid parsed message of With", parsed.Error())
+	}
+}
+
 func TestMarshal(t *testing.T) {
 	const (
 		innerMessage = "inner"
@@ -41,3 +67,23 @@ func TestMarshal(t *testing.T) {
 	b, _ := json.Marshal(outer)
 	t.Log(string(b))
 }
+
+func TestUnwrap(t *testing.T) {
+	const (
+		outerMessage = "outer"
+	)
+	outer := Wrap(io.EOF, outerMessage)
+
+	if !stderrors.Is(outer, io.EOF) {
+		t.Fatal("errors.Is can't find inner", outer)
+	}
+	if stderrors.Unwrap(outer) != io.EOF {
+		t.Fatal("invalid unwrapped error", stderrors.Unwrap(outer))
+	}
+
+	var pathErr *os.PathError
+	_, openErr := os.Open("unexistent_file")
+	if !stderrors.As(Wrap(Wrap(openErr, "middle"), outerMessage), &pathErr) {
+		t.Fatal("errors.As can't find inner", openErr)
+	}
+}
@@ -1,7 +1,7 @@
 package errors
 
 import (
-	"encoding/json"
+	stderrors "errors"
 	"fmt"
 )
 
@@ -9,6 +9,7 @@ type (
 	errorSource struct {
 		*errorType
 		source error
+		public string
 	}
 )
 
@@ -17,6 +18,19 @@ func (e *errorSource) Error() string {
 	return e.source.Error()
 }
 
+// Is reports whether the source matches the target.
+// It is used by errors.Is of the standard library.
+// The inner errors are checked by errors.Is through Unwrap.
+func (e *errorSource) Is(target error) bool {
+	return stderrors.Is(e.source, target)
+}
+
+// As finds the first error in the source chain that matches the target.
+// It is used by errors.As of the standard library.
+func (e *errorSource) As(target interface{}) bool {
+	return stderrors.As(e.source, target)
+}
+
 // NewAsSource returns a new error which is the source.
 func NewAsSource(msg string) error {
 	return newSource(nil, new(nil, msg, 1), 1)
@@ -62,6 +76,9 @@ func WrapBySourceMsgf(inner error, format string, a ...interface{}) error {
 }
 
 // SourceOf returns the source error of the err.
+// The explicit sources beneath the foreign errors such as fmt.Errorf with %w
+// and errors.Join are found by their Unwrap methods,
+// otherwise the foreign error is the source.
 func SourceOf(err error) error {
 	if e, ok := err.(*errorSource); ok {
 		return e.source
@@ -69,11 +86,18 @@ func SourceOf(err error) error {
 	if e, ok := err.(*collection); ok {
 		return e.source()
 	}
-	if e, ok := err.(*errorType); ok {
+	if e, ok := errorTypeOf(err); ok {
 		if e.inner != nil {
 			return SourceOf(e.inner)
 		}
-		return e
+		return err
+	}
+	if inners := unwrapForeign(err); inners != nil {
+		for _, inner := range inners {
+			if s := ExplicitSourceOf(inner); s != nil {
+				return s
+			}
+		}
 	}
 	return err
 }
@@ -87,37 +111,30 @@ func ExplicitSourceOf(err error) error {
 	if e, ok := err.(*collection); ok {
 		return e.explicitSource()
 	}
-	if e, ok := err.(*errorType); ok {
+	if e, ok := errorTypeOf(err); ok {
 		if e.inner != nil {
 			return ExplicitSourceOf(e.inner)
 		}
 		return nil
 	}
+	for _, inner := range unwrapForeign(err) {
+		if s := ExplicitSourceOf(inner); s != nil {
+			return s
+		}
+	}
 	return nil
 }
 
 // MarshalJSON implements json.Marshaler interface.
+// It marshals the error with all the stack by the default printer.
 func (e *errorSource) MarshalJSON() ([]byte, error) {
-	obj := struct {
-		Inner    *errMarshal `json:"inner"`
-		Callers  *callerInfo `json:"callers"`
-		Message  string      `json:"message"`
-		IsSource bool        `json:"isSource"`
-	}{
-		Inner: &errMarshal{
-			err:         e.inner,
-			callerCount: e.callerCount,
-		},
-		Callers:  e.info,
-		Message:  e.source.Error(),
-		IsSource: true,
-	}
-	return json.Marshal(&obj)
+	p := DefaultPrinter()
+	return newErrMarshal(p, e, p.stackCount()).MarshalJSON()
 }
 
 func newSource(inner error, source error, skip int) error {
 	return &errorSource{
-		errorType: new(inner, "", skip+1).(*errorType),
+		errorType: newErrorType(inner, "", skip+1),
 		source:    source,
 	}
 }
@@ -2,6 +2,9 @@ package errors
 
 import (
 	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
 	"testing"
 )
 
@@ -19,6 +22,22 @@ func TestSourceOf(t *testing.T) {
 	}
 }
 
+func TestSourceOfSentinel(t *testing.T) {
+	sentinel := New("sentinel")
+	if SourceOf(sentinel) != sentinel {
+		t.Fatal("invalid source of the sentinel", SourceOf(sentinel))
+	}
+	if SourceOf(Wrap(sentinel, "x")) != sentinel {
+		t.Fatal("invalid source of the wrapped sentinel", SourceOf(Wrap(sentinel, "x")))
+	}
+	if !stderrors.Is(SourceOf(Wrap(sentinel, "x")), sentinel) {
+		t.Fatal("errors.Is can't find the sentinel", SourceOf(Wrap(sentinel, "x")))
+	}
+	if SourceOf(Merge(Wrap(sentinel, "x"), New("other"))) != sentinel {
+		t.Fatal("invalid source of the collection", SourceOf(Merge(Wrap(sentinel, "x"), New("other"))))
+	}
+}
+
 func TestWrapAsSource(t *testing.T) {
 	const (
 		innerMessage  = "inner"
@@ -43,3 +62,27 @@ func TestWrapAsSource(t *testing.T) {
 	b, _ := json.Marshal(outer2)
 	t.Log(string(b))
 }
+
+func TestIsAsOfSource(t *testing.T) {
+	source := &httperror{
+		StatusCode: http.StatusBadRequest,
+		Message:    errMessageUserIsRequired,
+	}
+	e := Wrap(WrapBySourceError(io.ErrUnexpectedEOF, source), "outer")
+
+	var herr *httperror
+	if !stderrors.As(e, &herr) || herr != source {
+		t.Fatal("errors.As can't find source", herr)
+	}
+	if !stderrors.Is(e, source) {
+		t.Fatal("errors.Is can't find source", e)
+	}
+	if !stderrors.Is(e, io.ErrUnexpectedEOF) {
+		t.Fatal("errors.Is can't find inner", e)
+	}
+
+	s := AsSource(source)
+	if !stderrors.As(s, &herr) || herr != source {
+		t.Fatal("errors.As can't find source", herr)
+	}
+}
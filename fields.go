@@ -0,0 +1,122 @@
+package errors
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	badFieldKey    = "!BADKEY"
+	fieldFormat    = "%s=%v"
+	fieldSeparator = " "
+	fieldsPrefix   = "\t"
+)
+
+// With returns the err by new error which has the fields.
+// The keysAndValues are the pairs of the key and the value,
+// such as With(err, "userID", 10, "attempt", 3).
+// A key which is not string is treated as a value of "!BADKEY" key.
+// The message of the returned error is the message of the err.
+func With(err error, keysAndValues ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	e := newLocationOnly(err, 1)
+	e.fields = fieldsOf(keysAndValues)
+	return e
+}
+
+// WrapWithFields returns the err by new error which has msg and the fields.
+func WrapWithFields(err error, msg string, fields map[string]interface{}) error {
+	if err == nil {
+		return nil
+	}
+	e := newErrorType(err, msg, 1)
+	e.fields = copyFields(fields)
+	return e
+}
+
+// Fields returns the fields of the err and the inner errors.
+// The fields of outer errors override the fields of inner errors.
+// The fields of merged errors are collected in order.
+// Returns nil if no field is found.
+func Fields(err error) map[string]interface{} {
+	result := map[string]interface{}{}
+	collectFields(err, result)
+	if len(result) <= 0 {
+		return nil
+	}
+	return result
+}
+
+func collectFields(err error, result map[string]interface{}) {
+	if e, ok := errorTypeOf(err); ok {
+		collectFields(e.inner, result)
+		mergeFields(result, e.fields)
+		return
+	}
+	if e, ok := err.(*errorSource); ok {
+		collectFields(e.inner, result)
+		mergeFields(result, e.fields)
+		return
+	}
+	if c, ok := err.(*collection); ok {
+		for _, e := range c.errs {
+			collectFields(e, result)
+		}
+		return
+	}
+	for _, inner := range unwrapForeign(err) {
+		collectFields(inner, result)
+	}
+}
+
+func fieldsOf(keysAndValues []interface{}) map[string]interface{} {
+	result := map[string]interface{}{}
+	for index := 0; index < len(keysAndValues); index++ {
+		key, ok := keysAndValues[index].(string)
+		if !ok || index+1 >= len(keysAndValues) {
+			result[badFieldKey] = keysAndValues[index]
+			continue
+		}
+		result[key] = keysAndValues[index+1]
+		index++
+	}
+	return result
+}
+
+func copyFields(fields map[string]interface{}) map[string]interface{} {
+	if len(fields) <= 0 {
+		return nil
+	}
+	result := make(map[string]interface{}, len(fields))
+	mergeFields(result, fields)
+	return result
+}
+
+func mergeFields(dst map[string]interface{}, src map[string]interface{}) {
+	for key, value := range src {
+		dst[key] = value
+	}
+}
+
+// fieldsString returns the fields as "key=value" sorted by the keys.
+// Returns "" if no field exists.
+func fieldsString(fields map[string]interface{}) string {
+	if len(fields) <= 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for index, key := range keys {
+		pairs[index] = fmt.Sprintf(fieldFormat, key, fields[key])
+	}
+	return fieldsPrefix + strings.Join(pairs, fieldSeparator)
+}
@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWith(t *testing.T) {
+	inner := With(io.EOF, "userID", 10, "attempt", 1)
+	outer := WrapWithFields(inner, "outer", map[string]interface{}{
+		"attempt": 2,
+		"orderID": "A-1",
+	})
+
+	if inner.Error() != io.EOF.Error() {
+		t.Fatal("invalid message", inner)
+	}
+	if With(nil, "userID", 10) != nil {
+		t.Fatal("with nil returned error")
+	}
+
+	fields := Fields(outer)
+	if len(fields) != 3 || fields["userID"] != 10 || fields["attempt"] != 2 || fields["orderID"] != "A-1" {
+		t.Fatal("invalid fields", fields)
+	}
+	if Fields(io.EOF) != nil {
+		t.Fatal("fields of foreign error", Fields(io.EOF))
+	}
+
+	fields = Fields(With(io.EOF, "key", 1, 2, "last"))
+	if fields["key"] != 1 || fields[badFieldKey] != "last" {
+		t.Fatal("invalid bad key", fields)
+	}
+}
+
+func TestFieldsOfCollection(t *testing.T) {
+	c := Merge(With(io.EOF, "left", 1, "both", "left"), With(io.EOF, "right", 2, "both", "right"))
+	fields := Fields(Wrap(c, "outer"))
+	if fields["left"] != 1 || fields["right"] != 2 || fields["both"] != "right" {
+		t.Fatal("invalid fields", fields)
+	}
+}
+
+func TestFieldsOutput(t *testing.T) {
+	e := Wrap(With(New("inner"), "userID", 10, "attempt", 3), "outer")
+
+	s, _ := JSONAll(e)
+	obj := struct {
+		Inner struct {
+			Fields map[string]interface{} `json:"fields"`
+		} `json:"inner"`
+	}{}
+	json.Unmarshal([]byte(s), &obj)
+	if obj.Inner.Fields["userID"] != float64(10) {
+		t.Fatal("fields not marshalled", s)
+	}
+
+	parsed, _ := ParseJSON(s)
+	if Fields(parsed)["attempt"] != float64(3) {
+		t.Fatal("fields not parsed", Fields(parsed))
+	}
+
+	lines := strings.Split(StringWithInner(e), "\n")
+	if !strings.HasSuffix(lines[1], "\tinner\tattempt=3 userID=10") {
+		t.Fatal("fields not printed", lines[1])
+	}
+}
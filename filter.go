@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// runtimePackages are the packages which NoRuntimeFrames hides.
+	runtimePackages = []string{"runtime", "testing", "reflect"}
+)
+
+// IncludePackages returns a FrameFilter which accepts only the frames
+// of the packages which have one of the prefixes.
+// A prefix matches the package itself and its sub packages,
+// so "net/http" matches "net/http/httptest" but not "net/httputil".
+func IncludePackages(prefixes ...string) FrameFilter {
+	return func(function string, file string) bool {
+		return hasPackagePrefix(packageOf(function), prefixes)
+	}
+}
+
+// ExcludePackages returns a FrameFilter which hides the frames
+// of the packages which have one of the prefixes.
+func ExcludePackages(prefixes ...string) FrameFilter {
+	return func(function string, file string) bool {
+		return !hasPackagePrefix(packageOf(function), prefixes)
+	}
+}
+
+// IncludeFunctions returns a FrameFilter which accepts only the frames
+// whose function names match the pattern.
+func IncludeFunctions(pattern *regexp.Regexp) FrameFilter {
+	return func(function string, file string) bool {
+		return pattern.MatchString(function)
+	}
+}
+
+// ExcludeFunctions returns a FrameFilter which hides the frames
+// whose function names match the pattern.
+func ExcludeFunctions(pattern *regexp.Regexp) FrameFilter {
+	return func(function string, file string) bool {
+		return !pattern.MatchString(function)
+	}
+}
+
+// NoRuntimeFrames is a FrameFilter which hides the frames
+// of the runtime, testing and reflect packages such as runtime.goexit.
+func NoRuntimeFrames(function string, file string) bool {
+	return !hasPackagePrefix(packageOf(function), runtimePackages)
+}
+
+// AppOnlyFrames is a FrameFilter which hides the frames of the standard library.
+// The packages whose first path element has no dot are regarded as the standard library,
+// except the main package.
+func AppOnlyFrames(function string, file string) bool {
+	return !isStandardPackage(packageOf(function))
+}
+
+func hasPackagePrefix(pkg string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if pkg == prefix || strings.HasPrefix(pkg, strings.TrimSuffix(prefix, "/")+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func isStandardPackage(pkg string) bool {
+	if pkg == "" || pkg == mainPackage {
+		return false
+	}
+	first := pkg
+	if index := strings.Index(pkg, "/"); 0 <= index {
+		first = pkg[:index]
+	}
+	return !strings.Contains(first, ".")
+}
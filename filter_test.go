@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestPackageFilters(t *testing.T) {
+	tests := []struct {
+		filter   FrameFilter
+		function string
+		expected bool
+	}{
+		{IncludePackages("net/http"), "net/http.(*conn).serve", true},
+		{IncludePackages("net/http"), "net/http/httptest.NewServer", true},
+		{IncludePackages("net/http/"), "net/httputil.NewSingleHostReverseProxy", false},
+		{ExcludePackages("net/http"), "net/http.HandlerFunc.ServeHTTP", false},
+		{ExcludePackages("net/http"), "main.main", true},
+		{IncludeFunctions(regexp.MustCompile(`^main\.`)), "main.run", true},
+		{ExcludeFunctions(regexp.MustCompile(`\.func\d+$`)), "main.run.func1", false},
+		{NoRuntimeFrames, "runtime.goexit", false},
+		{NoRuntimeFrames, "testing.tRunner", false},
+		{NoRuntimeFrames, "net/http.(*conn).serve", true},
+		{AppOnlyFrames, "net/http.(*conn).serve", false},
+		{AppOnlyFrames, "main.main", true},
+		{AppOnlyFrames, "github.com/trimark-jp/errors.New", true},
+		{AppOnlyFrames, "github.com/foo/bar.F[go.shape.int]", true},
+	}
+	for _, test := range tests {
+		if actual := test.filter(test.function, ""); actual != test.expected {
+			t.Error("invalid filter result", test.function, actual)
+		}
+	}
+}
+
+func TestFilterBeforeMaxStack(t *testing.T) {
+	defer SetDefaultPrinter(nil)
+	p := NewPrinter().WithMaxStack(1).WithFrameFilter(NoRuntimeFrames)
+	SetDefaultPrinter(p)
+
+	e := New("error")
+	s, _ := JSONAll(e)
+	obj := struct {
+		Callers []*callerInfoItem `json:"callers"`
+	}{}
+	json.Unmarshal([]byte(s), &obj)
+	if len(obj.Callers) != 1 || obj.Callers[0].Function != "github.com/trimark-jp/errors.TestFilterBeforeMaxStack" {
+		t.Fatal("invalid callers", s)
+	}
+
+	p = NewPrinter().WithMaxStack(1).WithFrameFilter(ExcludePackages("github.com/trimark-jp/errors"))
+	SetDefaultPrinter(p)
+	e = New("error")
+	if s := e.(*leafError).info.String(); !strings.Contains(s, "testing.tRunner") {
+		t.Fatal("location not filtered", s)
+	}
+	if s := StringWithLocation(e); !strings.Contains(s, "testing.tRunner") {
+		t.Fatal("string not filtered", s)
+	}
+}
+
+func TestFilterOfNonDefaultPrinter(t *testing.T) {
+	defer SetDefaultPrinter(nil)
+	SetDefaultPrinter(NewPrinter().WithMaxStack(1))
+
+	e := New("error")
+	if n := len(e.(*leafError).info.pcs); n != 1 {
+		t.Fatal("frames captured without the filters", n)
+	}
+
+	SetDefaultPrinter(NewPrinter().WithMaxStack(1).WithCaptureStack(1 + filteredFramesMargin))
+	e = New("error")
+	p := NewPrinter().WithMaxStack(1).WithFrameFilter(ExcludePackages("github.com/trimark-jp/errors"))
+	if s := p.StringWithLocation(e); !strings.Contains(s, "testing.tRunner") {
+		t.Fatal("frames not captured for the filters", s)
+	}
+}
@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"fmt"
+)
+
+type (
+	// parsedWrapper is a foreign wrapper error rebuilt by ParseJSON.
+	// It keeps the Go type name of the original error.
+	parsedWrapper struct {
+		msg      string
+		typeName string
+		errs     []error
+		info     *callerInfo
+
+		// single is true if the original error has Unwrap() error.
+		single bool
+	}
+)
+
+const (
+	foreignLayerFormat = "%s\t(%s)"
+)
+
+// Error implements error interface.
+func (w *parsedWrapper) Error() string {
+	return w.msg
+}
+
+// Unwrap returns the inner errors.
+// It is used by errors.Is and errors.As of the standard library.
+func (w *parsedWrapper) Unwrap() []error {
+	return w.errs
+}
+
+// unwrapForeign returns the inner errors of the foreign err
+// by Unwrap() error or Unwrap() []error.
+// Returns nil if the err wraps nothing.
+func unwrapForeign(err error) []error {
+	if u, ok := err.(interface{ Unwrap() error }); ok {
+		if inner := u.Unwrap(); inner != nil {
+			return []error{inner}
+		}
+		return nil
+	}
+	if u, ok := err.(interface{ Unwrap() []error }); ok {
+		result := []error{}
+		for _, inner := range u.Unwrap() {
+			if inner != nil {
+				result = append(result, inner)
+			}
+		}
+		if len(result) <= 0 {
+			return nil
+		}
+		return result
+	}
+	return nil
+}
+
+// unwrapsSingle reports whether the foreign err has Unwrap() error
+// rather than Unwrap() []error.
+func unwrapsSingle(err error) bool {
+	if w, ok := err.(*parsedWrapper); ok {
+		return w.single
+	}
+	_, ok := err.(interface{ Unwrap() error })
+	return ok
+}
+
+// typeNameOf returns the Go type name of the foreign err.
+func typeNameOf(err error) string {
+	if w, ok := err.(*parsedWrapper); ok {
+		return w.typeName
+	}
+	return fmt.Sprintf("%T", err)
+}
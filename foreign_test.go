@@ -0,0 +1,113 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSourceOfForeignWrapper(t *testing.T) {
+	source := stderrors.New("source")
+	e := fmt.Errorf("handler: %w", WrapBySourceError(New("inner"), source))
+
+	if SourceOf(e) != source {
+		t.Fatal("invalid source", SourceOf(e))
+	}
+	if ExplicitSourceOf(e) != source {
+		t.Fatal("invalid explicit source", ExplicitSourceOf(e))
+	}
+
+	joined := stderrors.Join(New("first"), fmt.Errorf("second: %w", WrapBySourceError(New("inner"), source)))
+	if SourceOf(joined) != source || ExplicitSourceOf(joined) != source {
+		t.Fatal("invalid source of joined", SourceOf(joined))
+	}
+
+	e = fmt.Errorf("read: %w", io.EOF)
+	if SourceOf(e) != e || ExplicitSourceOf(e) != nil {
+		t.Fatal("invalid source of plain wrapper", SourceOf(e))
+	}
+	if CodeOf(fmt.Errorf("x: %w", WrapByCode(io.EOF, codeTestNotFound, "not found"))) != codeTestNotFound {
+		t.Fatal("code not found through foreign wrapper")
+	}
+	if Fields(fmt.Errorf("x: %w", With(io.EOF, "key", 1)))["key"] != 1 {
+		t.Fatal("fields not found through foreign wrapper")
+	}
+}
+
+type unwrappingStatusError struct {
+	status int
+	inner  error
+}
+
+func (e *unwrappingStatusError) Error() string {
+	return "status error"
+}
+
+func (e *unwrappingStatusError) HTTPStatus() int {
+	return e.status
+}
+
+func (e *unwrappingStatusError) Unwrap() error {
+	return e.inner
+}
+
+func TestSourceOfForeignStatusError(t *testing.T) {
+	source := &unwrappingStatusError{status: http.StatusNotFound, inner: io.EOF}
+	e := Wrap(source, "outer")
+
+	if SourceOf(e) != source {
+		t.Fatal("invalid source", SourceOf(e))
+	}
+	if HTTPStatusOf(e) != http.StatusNotFound {
+		t.Fatal("invalid status", HTTPStatusOf(e))
+	}
+}
+
+func TestStringWithInnerForeignWrapper(t *testing.T) {
+	e := fmt.Errorf("handler: %w", Wrap(New("inner"), "outer"))
+	lines := strings.Split(StringWithInner(e), "\n")
+	if len(lines) != 4 {
+		t.Fatal("invalid lines", lines)
+	}
+	if lines[0] != "handler: outer: inner\t(*fmt.wrapError)" {
+		t.Fatal("invalid foreign layer", lines[0])
+	}
+	if !strings.Contains(lines[1], "outer") || !strings.Contains(lines[2], "inner") {
+		t.Fatal("inner errors not printed", lines)
+	}
+
+	s := StringWithInner(stderrors.Join(New("first"), New("second")))
+	if !strings.Contains(s, "(*errors.joinError)") || !strings.Contains(s, "first") || !strings.Contains(s, "second") {
+		t.Fatal("joined errors not printed", s)
+	}
+}
+
+func TestJSONForeignWrapper(t *testing.T) {
+	source := stderrors.New("source")
+	e := Wrap(fmt.Errorf("handler: %w", WrapBySourceError(New("inner"), source)), "outer")
+
+	s, err := JSONAll(e)
+	if err != nil || !strings.Contains(s, `"goType":"*fmt.wrapError"`) || !strings.Contains(s, `"isSource":true`) {
+		t.Fatal("invalid json", s, err)
+	}
+
+	parsed, err := ParseJSON(s)
+	if err != nil {
+		t.Fatal("parse failed", err)
+	}
+	if SourceOf(parsed).Error() != source.Error() {
+		t.Fatal("invalid parsed source", SourceOf(parsed))
+	}
+	if again, _ := JSONAll(parsed); again != s {
+		t.Fatal("not round-tripped", again, s)
+	}
+
+	s, _ = JSONAll(stderrors.Join(io.EOF, io.ErrUnexpectedEOF))
+	parsed, _ = ParseJSON(s)
+	if !strings.Contains(s, `"errors":[`) || parsed.Error() != "EOF\nunexpected EOF" {
+		t.Fatal("invalid joined json", s)
+	}
+}
@@ -0,0 +1,131 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	messageChainSeparator = ": "
+)
+
+// Format implements fmt.Formatter interface.
+//
+//	%s, %v  the messages of the error and the inner errors
+//	%q      the quoted %s
+//	%+v     the messages with locations of the error and the inner errors,
+//	        or the message and the stack in the format of the Go panics
+//	        if the default printer has WithPanicStackFormat
+//	%#v     Go-syntax representation for debugging
+func (e *errorType) Format(s fmt.State, verb rune) {
+	format(s, verb, e)
+}
+
+// Format implements fmt.Formatter interface.
+// See errorType.Format for the verbs.
+func (l *leafError) Format(s fmt.State, verb rune) {
+	format(s, verb, l)
+}
+
+// Format implements fmt.Formatter interface.
+// See errorType.Format for the verbs.
+func (e *errorSource) Format(s fmt.State, verb rune) {
+	format(s, verb, e)
+}
+
+// Format implements fmt.Formatter interface.
+// See errorType.Format for the verbs.
+func (c *collection) Format(s fmt.State, verb rune) {
+	format(s, verb, c)
+}
+
+// format writes the err by the default printer,
+// so the messages are redacted if it has a redactor.
+func format(s fmt.State, verb rune, err error) {
+	p := DefaultPrinter()
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			if p.panicStack {
+				io.WriteString(s, p.panicString(err))
+				return
+			}
+			io.WriteString(s, p.StringWithInner(err))
+			return
+		}
+		if s.Flag('#') {
+			io.WriteString(s, p.redactMessage(goSyntax(err)))
+			return
+		}
+		io.WriteString(s, p.redactMessage(messageChain(err)))
+	case 's':
+		io.WriteString(s, p.redactMessage(messageChain(err)))
+	case 'q':
+		fmt.Fprintf(s, "%q", p.redactMessage(messageChain(err)))
+	default:
+		fmt.Fprintf(s, "%%!%c(%s)", verb, p.redactMessage(messageChain(err)))
+	}
+}
+
+// messageChain returns the messages of the err and the inner errors.
+func messageChain(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	if e, ok := errorTypeOf(err); ok {
+		return joinMessage(e.msg, e.inner)
+	}
+	if e, ok := err.(*errorSource); ok {
+		return joinMessage(e.Error(), e.inner)
+	}
+	if c, ok := err.(*collection); ok {
+		msgs := make([]string, len(c.errs))
+		for index, e := range c.errs {
+			msgs[index] = messageChain(e)
+		}
+		return fmt.Sprintf(collectionStringFormat,
+			strings.Join(msgs, collectionSeparator))
+	}
+	return err.Error()
+}
+
+func joinMessage(msg string, inner error) string {
+	if inner == nil {
+		return msg
+	}
+	innerMessage := messageChain(inner)
+	if msg == "" {
+		return innerMessage
+	}
+	if innerMessage == "" {
+		return msg
+	}
+	return msg + messageChainSeparator + innerMessage
+}
+
+// goSyntax returns Go-syntax representation of the err.
+func goSyntax(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+
+	if e, ok := errorTypeOf(err); ok {
+		return fmt.Sprintf("&errors.errorType{msg:%q, caller:%q, inner:%s}",
+			e.msg, e.info.String(), goSyntax(e.inner))
+	}
+	if e, ok := err.(*errorSource); ok {
+		return fmt.Sprintf("&errors.errorSource{source:%s, caller:%q, inner:%s}",
+			goSyntax(e.source), e.info.String(), goSyntax(e.inner))
+	}
+	if c, ok := err.(*collection); ok {
+		items := make([]string, len(c.errs))
+		for index, e := range c.errs {
+			items[index] = goSyntax(e)
+		}
+		return fmt.Sprintf("&errors.collection{errs:[]error{%s}}",
+			strings.Join(items, collectionSeparator))
+	}
+	return fmt.Sprintf("%#v", err)
+}
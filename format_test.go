@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	const (
+		innerMessage  = "inner"
+		middleMessage = "middle"
+		outerMessage  = "outer"
+	)
+	inner := Wrap(io.EOF, innerMessage)
+	middle := WrapBySourceMsg(inner, middleMessage)
+	outer := Wrap(middle, outerMessage)
+
+	expected := "outer: middle: inner: EOF"
+	if s := fmt.Sprintf("%v", outer); s != expected {
+		t.Fatal("invalid v verb", s)
+	}
+	if s := fmt.Sprintf("%s", outer); s != expected {
+		t.Fatal("invalid s verb", s)
+	}
+	if s := fmt.Sprintf("%q", outer); s != `"`+expected+`"` {
+		t.Fatal("invalid q verb", s)
+	}
+	if s := fmt.Sprintf("%+v", outer); s != StringWithInner(outer) {
+		t.Fatal("invalid +v verb", s)
+	}
+
+	s := fmt.Sprintf("%#v", outer)
+	if !strings.HasPrefix(s, `&errors.errorType{msg:"outer", caller:"github.com/trimark-jp/errors/format_test.go:`) {
+		t.Fatal("invalid #v verb", s)
+	}
+	if !strings.Contains(s, `&errors.errorSource{source:&errors.errorType{msg:"middle"`) {
+		t.Fatal("invalid #v verb", s)
+	}
+}
+
+func TestFormatCollection(t *testing.T) {
+	c := Merge(Wrap(io.EOF, "left"), New("right"))
+
+	expected := fmt.Sprintf(collectionStringFormat, "left: EOF, right")
+	if s := fmt.Sprintf("%v", c); s != expected {
+		t.Fatal("invalid v verb", s)
+	}
+	if s := fmt.Sprintf("%+v", c); s != StringWithInner(c) {
+		t.Fatal("invalid +v verb", s)
+	}
+}
@@ -0,0 +1,115 @@
+package errors
+
+import (
+	"context"
+	"sync"
+)
+
+type (
+	// Group runs functions in goroutines and collects all their errors.
+	// The zero value is a Group without context and without limit.
+	Group struct {
+		wg     sync.WaitGroup
+		ctx    context.Context
+		cancel context.CancelFunc
+		sem    chan struct{}
+
+		mutex sync.Mutex
+		errs  []error
+	}
+)
+
+// GroupWithContext returns a new Group and a context derived from the ctx.
+// The context is canceled when a function returns an error or panics,
+// or when Wait returns.
+func GroupWithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(ctx)
+	return &Group{
+		ctx:    ctx,
+		cancel: cancel,
+	}, ctx
+}
+
+// SetLimit limits the number of the running goroutines to n.
+// A negative n means no limit.
+// It must be called before Go or GoCtx.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go calls the f in a new goroutine.
+// If the limit is reached, Go blocks until a goroutine exits.
+// A panic in the f is recovered and collected as an error.
+func (g *Group) Go(f func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+		defer func() {
+			if r := recover(); r != nil {
+				g.add(newPanic(r))
+			}
+		}()
+		g.add(f())
+	}()
+}
+
+// GoCtx calls the f with the context of the group in a new goroutine.
+// The context is context.Background if the group has no context.
+func (g *Group) GoCtx(f func(ctx context.Context) error) {
+	ctx := g.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	g.Go(func() error {
+		return f(ctx)
+	})
+}
+
+// Wait waits for all the goroutines.
+// It returns an error which has all the errors in completion order.
+// Returns nil if no error occurred.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel()
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	if len(g.errs) <= 0 {
+		return nil
+	}
+
+	result := newCollection()
+	result.errs = append(result.errs, g.errs...)
+	return result
+}
+
+func (g *Group) add(err error) {
+	if err == nil {
+		return
+	}
+
+	g.mutex.Lock()
+	g.errs = append(g.errs, err)
+	g.mutex.Unlock()
+
+	if g.cancel != nil {
+		g.cancel()
+	}
+}
+
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
@@ -0,0 +1,113 @@
+package errors
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	g := &Group{}
+	g.Go(func() error {
+		return nil
+	})
+	if err := g.Wait(); err != nil {
+		t.Fatal("group without failure returned error", err)
+	}
+
+	g = &Group{}
+	g.Go(func() error {
+		return io.EOF
+	})
+	g.Go(func() error {
+		return nil
+	})
+	g.Go(func() error {
+		return io.ErrUnexpectedEOF
+	})
+	err := g.Wait()
+	c, ok := err.(*collection)
+	if !ok {
+		t.Fatal("invalid error type", err)
+	}
+	if len(c.errs) != 2 {
+		t.Fatal("invalid error count", err)
+	}
+}
+
+func TestGroupPanic(t *testing.T) {
+	g := &Group{}
+	g.Go(func() error {
+		return panicInGroup()
+	})
+	err := g.Wait()
+
+	e := err.(*collection).errs[0].(*leafError)
+	if e.Error() != "panic: boom" {
+		t.Fatal("invalid panic message", e)
+	}
+	first := e.info.Items()[0]
+	if first.Function != "github.com/trimark-jp/errors.panicInGroup" {
+		t.Fatal("invalid panic location", first.Function)
+	}
+}
+
+func panicInGroup() error {
+	panic("boom")
+}
+
+func TestGroupWithContext(t *testing.T) {
+	g, ctx := GroupWithContext(context.Background())
+	g.GoCtx(func(ctx context.Context) error {
+		return io.EOF
+	})
+	g.GoCtx(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	err := g.Wait()
+	if len(err.(*collection).errs) != 2 {
+		t.Fatal("invalid error count", err)
+	}
+	if ctx.Err() == nil {
+		t.Fatal("context not canceled")
+	}
+}
+
+func TestGroupLimit(t *testing.T) {
+	const (
+		limit = 2
+		count = 10
+	)
+	g := &Group{}
+	g.SetLimit(limit)
+
+	mutex := sync.Mutex{}
+	running := 0
+	max := 0
+	for i := 0; i < count; i++ {
+		g.Go(func() error {
+			mutex.Lock()
+			running++
+			if max < running {
+				max = running
+			}
+			mutex.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if limit < max {
+		t.Fatal("limit exceeded", max)
+	}
+}
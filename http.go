@@ -0,0 +1,178 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+type (
+	// HandlerFunc is an HTTP handler which returns an error.
+	// The error is written to the response by a zero HTTPResponder.
+	HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+	// HTTPStatuser is implemented by the source errors
+	// which know their HTTP status.
+	HTTPStatuser interface {
+		HTTPStatus() int
+	}
+
+	// HTTPResponder writes errors to HTTP responses.
+	// The status is chosen by the HTTPStatus method of the source error,
+	// or by the registered HTTP status of the code of the error.
+	// The zero value is ready to use.
+	HTTPResponder struct {
+		// FallbackStatus is the status for unknown errors.
+		// If it is zero, http.StatusInternalServerError is used.
+		FallbackStatus int
+
+		// FallbackMessage is the message for unknown errors.
+		// If it is empty, the status text is used.
+		FallbackMessage string
+
+		// ErrorLog logs the traces of the 5xx errors.
+		// If it is nil, the standard logger is used.
+		ErrorLog *log.Logger
+
+		// Problem writes the errors as application/problem+json if it is not nil.
+		Problem *ProblemBuilder
+	}
+)
+
+const (
+	httpContentType = "text/plain; charset=utf-8"
+	httpLogFormat   = "%s %s: %s"
+)
+
+// ServeHTTP implements http.Handler interface.
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	(&HTTPResponder{}).Handler(f).ServeHTTP(w, r)
+}
+
+// Handler returns an http.Handler which calls the f
+// and writes the returned error by the responder.
+// A panic in the f is also written as an error.
+func (h *HTTPResponder) Handler(f HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := h.call(f, w, r); err != nil {
+			h.WriteError(w, r, err)
+		}
+	})
+}
+
+// Middleware returns an http.Handler which recovers the panics in the next
+// and writes them as errors by the responder.
+func (h *HTTPResponder) Middleware(next http.Handler) http.Handler {
+	return h.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		next.ServeHTTP(w, r)
+		return nil
+	})
+}
+
+// WriteError writes the status and the public message of the err.
+// The traces of the 5xx errors are logged.
+func (h *HTTPResponder) WriteError(w http.ResponseWriter, r *http.Request, err error) {
+	status, message := h.Response(err)
+	if http.StatusInternalServerError <= status {
+		h.logf(httpLogFormat, r.Method, r.URL.Path, StringWithInner(err))
+	}
+
+	if h.Problem != nil {
+		p := h.Problem.build(err, h)
+		p.Instance = r.URL.RequestURI()
+		w.Header().Set("Content-Type", ProblemContentType)
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(p)
+		return
+	}
+
+	w.Header().Set("Content-Type", httpContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	fmt.Fprint(w, message)
+}
+
+// Response returns the HTTP status and the public message of the err.
+// The public message of WrapPublic is preferred.
+// Otherwise the 4xx errors have the message of the explicit source
+// or the registered message of the code,
+// and the others have the status text.
+func (h *HTTPResponder) Response(err error) (int, string) {
+	status := HTTPStatusOf(err)
+	if HasPublicMessage(err) {
+		if status == 0 {
+			status, _ = h.fallback()
+		}
+		return status, PublicMessage(err)
+	}
+	if status == 0 {
+		return h.fallback()
+	}
+	if http.StatusInternalServerError <= status {
+		return status, http.StatusText(status)
+	}
+	return status, clientErrorMessage(err, status)
+}
+
+// clientErrorMessage returns the message of the 4xx err which is safe to write.
+// The messages of the implicit sources such as the errors of the drivers
+// and the wrap messages such as of WrapByCode are never written.
+func clientErrorMessage(err error, status int) string {
+	if s := ExplicitSourceOf(err); s != nil {
+		return s.Error()
+	}
+	if info, ok := CodeInfoOf(err); ok && info.Message != "" {
+		return info.Message
+	}
+	return http.StatusText(status)
+}
+
+// HTTPStatusOf returns the HTTP status of the err.
+// The HTTPStatus method of the source error is preferred,
+// then the registered HTTP status of the code is used.
+// Returns 0 if the status is unknown.
+func HTTPStatusOf(err error) int {
+	if s, ok := SourceOf(err).(HTTPStatuser); ok {
+		if status := s.HTTPStatus(); status != 0 {
+			return status
+		}
+	}
+	if info, ok := CodeInfoOf(err); ok {
+		return info.HTTPStatus
+	}
+	return 0
+}
+
+func (h *HTTPResponder) fallback() (int, string) {
+	status := h.FallbackStatus
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	message := h.FallbackMessage
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	return status, message
+}
+
+// call calls the f and recovers the panic except http.ErrAbortHandler.
+func (h *HTTPResponder) call(f HandlerFunc, w http.ResponseWriter, r *http.Request) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+			err = Merge(err, Recover(recovered))
+		}
+	}()
+	return f(w, r)
+}
+
+func (h *HTTPResponder) logf(format string, a ...interface{}) {
+	if h.ErrorLog != nil {
+		h.ErrorLog.Printf(format, a...)
+		return
+	}
+	log.Printf(format, a...)
+}
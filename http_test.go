@@ -0,0 +1,158 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type statusError struct {
+	status  int
+	message string
+}
+
+func (e *statusError) Error() string {
+	return e.message
+}
+
+func (e *statusError) HTTPStatus() int {
+	return e.status
+}
+
+func TestHandlerFunc(t *testing.T) {
+	f := HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		err := WrapBySourceError(io.ErrUnexpectedEOF, &statusError{
+			status:  http.StatusBadRequest,
+			message: errMessageInvalidUserIDFormat,
+		})
+		return Wrap(err, errMessageLoginFailed)
+	})
+
+	w := httptest.NewRecorder()
+	f.ServeHTTP(w, httptest.NewRequest("GET", "/login", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatal("invalid status", w.Code)
+	}
+	if w.Body.String() != errMessageInvalidUserIDFormat {
+		t.Fatal("invalid body", w.Body.String())
+	}
+}
+
+func TestHTTPResponderCode(t *testing.T) {
+	h := &HTTPResponder{}
+	handler := h.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return Wrap(NewCode(codeTestNotFound, ""), "lookup failed")
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/users/1", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatal("invalid status", w.Code)
+	}
+	if w.Body.String() != "not found" {
+		t.Fatal("invalid body", w.Body.String())
+	}
+}
+
+func TestHTTPResponderImplicitSource(t *testing.T) {
+	h := &HTTPResponder{}
+
+	status, message := h.Response(WrapByCode(fmt.Errorf("pq: password=hunter2 at 10.0.0.5"), codeTestNotFound, ""))
+	if status != http.StatusNotFound || message != "not found" {
+		t.Fatal("implicit source written", status, message)
+	}
+
+	status, message = h.Response(Merge(New("db dsn user:pw@10.0.0.5"), NewCode(codeTestInvalid, "bad")))
+	if status != http.StatusBadRequest || message != "invalid" {
+		t.Fatal("implicit source written", status, message)
+	}
+
+	status, message = h.Response(WrapByCode(New("no rows"), codeTestNotFound, "lookup of user 42 in shard users_3 failed"))
+	if status != http.StatusNotFound || message != "not found" {
+		t.Fatal("implicit source written", status, message)
+	}
+
+	status, message = h.Response(Wrap(&statusError{status: http.StatusConflict, message: "pq: duplicate key"}, "insert"))
+	if status != http.StatusConflict || message != http.StatusText(http.StatusConflict) {
+		t.Fatal("implicit source written", status, message)
+	}
+}
+
+func TestHTTPResponderFallback(t *testing.T) {
+	logBuf := &bytes.Buffer{}
+	h := &HTTPResponder{
+		FallbackStatus:  http.StatusServiceUnavailable,
+		FallbackMessage: "try again later",
+		ErrorLog:        log.New(logBuf, "", 0),
+	}
+	handler := h.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return Wrap(New("secret internal detail"), "query failed")
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/orders", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatal("invalid status", w.Code)
+	}
+	if w.Body.String() != "try again later" {
+		t.Fatal("invalid body", w.Body.String())
+	}
+	if !strings.Contains(logBuf.String(), "secret internal detail") {
+		t.Fatal("trace not logged", logBuf.String())
+	}
+}
+
+func TestHTTPResponderInternalError(t *testing.T) {
+	logBuf := &bytes.Buffer{}
+	h := &HTTPResponder{
+		ErrorLog: log.New(logBuf, "", 0),
+	}
+	handler := h.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return AsSource(&statusError{
+			status:  http.StatusBadGateway,
+			message: "upstream password=secret",
+		})
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusBadGateway {
+		t.Fatal("invalid status", w.Code)
+	}
+	if w.Body.String() != http.StatusText(http.StatusBadGateway) {
+		t.Fatal("internal message leaked", w.Body.String())
+	}
+}
+
+func TestHTTPMiddleware(t *testing.T) {
+	logBuf := &bytes.Buffer{}
+	h := &HTTPResponder{
+		ErrorLog: log.New(logBuf, "", 0),
+	}
+	handler := h.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatal("invalid status", w.Code)
+	}
+	if !strings.Contains(logBuf.String(), "GET /panic") || !strings.Contains(logBuf.String(), "panic: boom") {
+		t.Fatal("panic not logged", logBuf.String())
+	}
+
+	defer func() {
+		if recover() != http.ErrAbortHandler {
+			t.Fatal("http.ErrAbortHandler is recovered")
+		}
+	}()
+	h.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	})).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
@@ -0,0 +1,257 @@
+package errors
+
+import (
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+type (
+	// moduleInfo is a module of the running binary.
+	moduleInfo struct {
+		path    string
+		version string
+
+		// replace is the module which replaces the module by the replace directive.
+		// Its files are in the module cache by its own path and version.
+		replace *moduleInfo
+	}
+
+	// buildModules is the modules of the running binary read from the build info.
+	buildModules struct {
+		mainPackage string
+		main        *moduleInfo
+		deps        []*moduleInfo
+	}
+)
+
+const (
+	mainPackage       = "main"
+	develVersion      = "(devel)"
+	moduleCacheMarker = "/pkg/mod/"
+	vendorMarker      = "/vendor/"
+)
+
+var (
+	buildModulesOnce  sync.Once
+	buildModulesValue *buildModules
+)
+
+// TrimModulePath is a PathTrimmer which uses the build info of the binary.
+// It trims the path to module/path@version/file.go for the dependencies
+// in the module cache or the vendor directory,
+// to module/path/file.go for the main module,
+// and to package/file.go for the standard library.
+// Paths built with -trimpath are already trimmed and returned as is.
+// Otherwise it falls back to TrimGOPATH.
+func TrimModulePath(filePath string, function string) string {
+	slashed := filepath.ToSlash(filePath)
+	if !isAbsPath(slashed) {
+		return slashed
+	}
+
+	modules := readBuildModules()
+	if trimmed, ok := modules.trimModuleCache(slashed); ok {
+		return trimmed
+	}
+	if trimmed, ok := modules.trimVendor(slashed); ok {
+		return trimmed
+	}
+	if trimmed, ok := trimGOROOT(slashed); ok {
+		return trimmed
+	}
+	if trimmed, ok := modules.trimByPackage(slashed, function); ok {
+		return trimmed
+	}
+	return trimGOPATHProbably(filePath, function)
+}
+
+// TrimGOPATH is a PathTrimmer which guesses the package from the function name
+// and trims the path before the package.
+func TrimGOPATH(filePath string, function string) string {
+	return trimGOPATHProbably(filePath, function)
+}
+
+func readBuildModules() *buildModules {
+	buildModulesOnce.Do(func() {
+		buildModulesValue = &buildModules{}
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		buildModulesValue.mainPackage = info.Path
+		if info.Main.Path != "" {
+			buildModulesValue.main = &moduleInfo{
+				path:    info.Main.Path,
+				version: info.Main.Version,
+			}
+		}
+		for _, dep := range info.Deps {
+			m := &moduleInfo{
+				path:    dep.Path,
+				version: dep.Version,
+			}
+			if dep.Replace != nil {
+				m.replace = &moduleInfo{
+					path:    dep.Replace.Path,
+					version: dep.Replace.Version,
+				}
+				if dep.Replace.Version != "" {
+					m.version = dep.Replace.Version
+				}
+			}
+			buildModulesValue.deps = append(buildModulesValue.deps, m)
+		}
+	})
+	return buildModulesValue
+}
+
+// trimModuleCache trims the path in the module cache,
+// such as $GOPATH/pkg/mod/github.com/!foo/bar@v1.2.3/file.go.
+func (b *buildModules) trimModuleCache(slashed string) (string, bool) {
+	for _, dep := range b.deps {
+		m := dep.cached()
+		if m.version == "" {
+			continue
+		}
+		dir := "/" + escapeModulePath(m.path) + "@" + m.version + "/"
+		index := strings.Index(slashed, dir)
+		if 0 <= index {
+			return m.path + "@" + m.version + slashed[index+len(dir)-1:], true
+		}
+	}
+
+	index := strings.LastIndex(slashed, moduleCacheMarker)
+	if index < 0 {
+		return "", false
+	}
+	rest := slashed[index+len(moduleCacheMarker):]
+	if !strings.Contains(rest, "@") {
+		return "", false
+	}
+	return unescapeModulePath(rest), true
+}
+
+// cached returns the module whose files are in the module cache,
+// the replacing module if the m is replaced.
+func (m *moduleInfo) cached() *moduleInfo {
+	if m.replace != nil {
+		return m.replace
+	}
+	return m
+}
+
+// trimVendor trims the path in the vendor directory,
+// and adds the version of the module.
+func (b *buildModules) trimVendor(slashed string) (string, bool) {
+	index := strings.LastIndex(slashed, vendorMarker)
+	if index < 0 {
+		return "", false
+	}
+	rest := slashed[index+len(vendorMarker):]
+	for _, dep := range b.deps {
+		if strings.HasPrefix(rest, dep.path+"/") && dep.version != "" {
+			return dep.path + "@" + dep.version + rest[len(dep.path):], true
+		}
+	}
+	return rest, true
+}
+
+// trimByPackage trims the path by the package of the function
+// if the package is in the main module.
+func (b *buildModules) trimByPackage(slashed string, function string) (string, bool) {
+	if b.main == nil {
+		return "", false
+	}
+
+	pkg := packageOf(function)
+	if pkg == mainPackage {
+		pkg = b.mainPackage
+	}
+	if pkg != b.main.path && !strings.HasPrefix(pkg, b.main.path+"/") {
+		return "", false
+	}
+
+	dir := path.Dir(slashed)
+	subDir := pkg[len(b.main.path):]
+	if !strings.HasSuffix(dir, subDir) {
+		return "", false
+	}
+
+	modulePath := b.main.path
+	if b.main.version != "" && b.main.version != develVersion {
+		modulePath += "@" + b.main.version
+	}
+	return modulePath + subDir + "/" + path.Base(slashed), true
+}
+
+// trimGOROOT trims the path of the standard library.
+func trimGOROOT(slashed string) (string, bool) {
+	goroot := filepath.ToSlash(runtime.GOROOT())
+	if goroot == "" {
+		return "", false
+	}
+	prefix := strings.TrimSuffix(goroot, "/") + "/src/"
+	if !strings.HasPrefix(slashed, prefix) {
+		return "", false
+	}
+	return slashed[len(prefix):], true
+}
+
+// packageOf returns the package path of the function name,
+// such as github.com/foo/bar for github.com/foo/bar.(*T).Method.
+func packageOf(function string) string {
+	// type parameters such as F[go.shape.*github.com/foo/bar.T]
+	if index := strings.Index(function, "["); 0 <= index {
+		function = function[:index]
+	}
+	lastSlashIndex := strings.LastIndex(function, "/")
+	dotIndex := strings.Index(function[lastSlashIndex+1:], ".")
+	if dotIndex < 0 {
+		return function
+	}
+	return function[:lastSlashIndex+1+dotIndex]
+}
+
+func isAbsPath(slashed string) bool {
+	if strings.HasPrefix(slashed, "/") {
+		return true
+	}
+	// Windows paths such as C:/path/to/file.go
+	return 2 < len(slashed) && slashed[1] == ':' && slashed[2] == '/'
+}
+
+// escapeModulePath escapes the upper case letters as the module cache does.
+func escapeModulePath(modulePath string) string {
+	buf := &strings.Builder{}
+	for _, r := range modulePath {
+		if unicode.IsUpper(r) {
+			buf.WriteByte('!')
+			r = unicode.ToLower(r)
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
+
+// unescapeModulePath is the reverse of escapeModulePath.
+func unescapeModulePath(escaped string) string {
+	buf := &strings.Builder{}
+	upper := false
+	for _, r := range escaped {
+		if r == '!' {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		buf.WriteRune(r)
+	}
+	return buf.String()
+}
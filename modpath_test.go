@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTrimModulePath(t *testing.T) {
+	e := New("error").(*leafError)
+	items := e.info.Items()
+
+	first := items[0]
+	if s := TrimModulePath(first.path, first.Function); s != "github.com/trimark-jp/errors/modpath_test.go" {
+		t.Fatal("invalid main module path", s)
+	}
+	last := items[len(items)-2]
+	if s := TrimModulePath(last.path, last.Function); !strings.HasPrefix(s, "testing/") {
+		t.Fatal("invalid standard library path", s)
+	}
+
+	if s := TrimModulePath("github.com/foo/bar@v1.0.0/bar.go", "github.com/foo/bar.F"); s != "github.com/foo/bar@v1.0.0/bar.go" {
+		t.Fatal("trimmed path is changed", s)
+	}
+}
+
+func TestTrimModulePathByBuildInfo(t *testing.T) {
+	modules := &buildModules{
+		mainPackage: "example.com/app/cmd/app",
+		main: &moduleInfo{
+			path:    "example.com/app",
+			version: "v1.0.0",
+		},
+		deps: []*moduleInfo{
+			{path: "github.com/Foo/bar", version: "v1.2.3"},
+			{path: "github.com/baz/qux", version: "v0.1.0"},
+			{path: "github.com/old/lib", version: "v1.1.0", replace: &moduleInfo{path: "github.com/Fork/lib", version: "v1.1.1"}},
+			{path: "github.com/local/lib", version: "v1.0.0", replace: &moduleInfo{path: "../lib"}},
+		},
+	}
+
+	s, ok := modules.trimModuleCache("/home/user/go/pkg/mod/github.com/!foo/bar@v1.2.3/sub/file.go")
+	if !ok || s != "github.com/Foo/bar@v1.2.3/sub/file.go" {
+		t.Fatal("invalid module cache path", s)
+	}
+	s, ok = modules.trimModuleCache("/cache/github.com/!foo/bar@v1.2.3/file.go")
+	if !ok || s != "github.com/Foo/bar@v1.2.3/file.go" {
+		t.Fatal("invalid custom module cache path", s)
+	}
+	s, ok = modules.trimModuleCache("/cache/github.com/!fork/lib@v1.1.1/file.go")
+	if !ok || s != "github.com/Fork/lib@v1.1.1/file.go" {
+		t.Fatal("invalid replaced module cache path", s)
+	}
+	s, ok = modules.trimModuleCache("/home/user/go/pkg/mod/example.com/!other@v2.0.0/file.go")
+	if !ok || s != "example.com/Other@v2.0.0/file.go" {
+		t.Fatal("invalid unknown module cache path", s)
+	}
+
+	s, ok = modules.trimVendor("/src/app/vendor/github.com/baz/qux/file.go")
+	if !ok || s != "github.com/baz/qux@v0.1.0/file.go" {
+		t.Fatal("invalid vendor path", s)
+	}
+
+	s, ok = modules.trimByPackage("/src/app/internal/file.go", "example.com/app/internal.(*T).F")
+	if !ok || s != "example.com/app@v1.0.0/internal/file.go" {
+		t.Fatal("invalid main module path", s)
+	}
+	s, ok = modules.trimByPackage("/src/app/cmd/app/main.go", "main.main")
+	if !ok || s != "example.com/app@v1.0.0/cmd/app/main.go" {
+		t.Fatal("invalid main package path", s)
+	}
+	if _, ok = modules.trimByPackage("/src/other/file.go", "example.com/other.F"); ok {
+		t.Fatal("trimmed path out of main module")
+	}
+}
+
+func TestPackageOf(t *testing.T) {
+	cases := map[string]string{
+		"github.com/foo/bar.F":                             "github.com/foo/bar",
+		"github.com/foo/bar.(*T).Method":                   "github.com/foo/bar",
+		"github.com/foo/bar.F.func1":                       "github.com/foo/bar",
+		"github.com/foo/bar.G[go.shape.*github.com/x/y.T]": "github.com/foo/bar",
+		"main.main":      "main",
+		"runtime.goexit": "runtime",
+	}
+	for function, expected := range cases {
+		if pkg := packageOf(function); pkg != expected {
+			t.Fatal("invalid package", function, pkg)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package errors
+
+import "fmt"
+
+const (
+	panicMessageFormat = "panic: %v"
+)
+
+// Recover returns a new error from the recovered value.
+// Returns nil if the recovered value is nil.
+// It must be called in the deferred function which recovered.
+//
+//	defer func() {
+//		if r := recover(); r != nil {
+//			err = errors.Recover(r)
+//		}
+//	}()
+func Recover(recovered interface{}) error {
+	if recovered == nil {
+		return nil
+	}
+	return newPanic(recovered)
+}
+
+// RecoverTo recovers a panic and merges the error from the panic into the *errp.
+// It must be deferred directly.
+//
+//	defer errors.RecoverTo(&err)
+func RecoverTo(errp *error) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+	*errp = Merge(*errp, newPanic(recovered))
+}
+
+// newPanic returns a new error from the recovered value.
+// The callers are the frames of the panicking goroutine.
+// If the value is an error, the value becomes the inner.
+// It must be called in the deferred function which recovered.
+func newPanic(recovered interface{}) error {
+	inner, _ := recovered.(error)
+	e := &errorType{
+		inner: inner,
+		msg:   fmt.Sprintf(panicMessageFormat, recovered),
+		info:  panicCaller(),
+	}
+	return e.typed()
+}
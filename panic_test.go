@@ -0,0 +1,86 @@
+package errors
+
+import (
+	"io"
+	"net/http"
+	"testing"
+)
+
+func TestRecoverTo(t *testing.T) {
+	err := recoverToByPanic("boom")
+	e, ok := err.(*leafError)
+	if !ok {
+		t.Fatal("invalid error type", err)
+	}
+	if e.Error() != "panic: boom" {
+		t.Fatal("invalid message", e)
+	}
+	if e.inner != nil {
+		t.Fatal("invalid inner", e.inner)
+	}
+	first := e.info.Items()[0]
+	if first.Function != "github.com/trimark-jp/errors.panicWith" {
+		t.Fatal("invalid panic location", first.Function)
+	}
+}
+
+func TestRecoverToWithError(t *testing.T) {
+	source := &httperror{
+		StatusCode: http.StatusBadRequest,
+		Message:    errMessageUserIsRequired,
+	}
+	err := recoverToByPanic(AsSource(source))
+	if SourceOf(err) != source {
+		t.Fatal("invalid source", SourceOf(err))
+	}
+
+	err = recoverToMerged(io.EOF)
+	c, ok := err.(*collection)
+	if !ok || len(c.errs) != 2 {
+		t.Fatal("not merged", err)
+	}
+	if c.errs[0] != io.ErrClosedPipe || c.errs[1].(*errorType).inner != io.EOF {
+		t.Fatal("invalid merged errors", err)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	if Recover(nil) != nil {
+		t.Fatal("recovered nil")
+	}
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = Recover(r)
+			}
+		}()
+		panicWith(io.EOF)
+		return nil
+	}()
+	e := err.(*errorType)
+	if e.inner != io.EOF {
+		t.Fatal("invalid inner", e.inner)
+	}
+	if e.info.Items()[0].Function != "github.com/trimark-jp/errors.panicWith" {
+		t.Fatal("invalid panic location", e.info.Items()[0].Function)
+	}
+}
+
+func recoverToByPanic(v interface{}) (err error) {
+	defer RecoverTo(&err)
+	panicWith(v)
+	return nil
+}
+
+func recoverToMerged(v interface{}) (err error) {
+	defer RecoverTo(&err)
+	err = io.ErrClosedPipe
+	panicWith(v)
+	return err
+}
+
+//go:noinline
+func panicWith(v interface{}) {
+	panic(v)
+}
@@ -0,0 +1,161 @@
+package errors
+
+import (
+	"bufio"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type (
+	// panicParser parses the output of the Go panics line by line.
+	panicParser struct {
+		message    []string
+		inMessage  bool
+		goroutines []*parsedGoroutine
+		function   string
+	}
+
+	// parsedGoroutine is a goroutine of the panic output.
+	parsedGoroutine struct {
+		id        int
+		state     string
+		createdBy string
+		items     []*callerInfoItem
+	}
+)
+
+const (
+	panicPrefix       = "panic: "
+	createdByPrefix   = "created by "
+	goroutineFormat   = "goroutine %d [%s]"
+	goroutineKey      = "goroutine"
+	goroutineStateKey = "state"
+	createdByKey      = "createdBy"
+)
+
+var (
+	// ErrNoGoroutine is returned by ParsePanic if the input has no goroutine.
+	ErrNoGoroutine = stderrors.New("errors: no goroutine in the panic output")
+
+	goroutinePattern = regexp.MustCompile(`^goroutine (\d+)(?: .*)? \[([^\]]*)\]:$`)
+	functionPattern  = regexp.MustCompile(`^(\S.*)\((.*)\)$`)
+	filePattern      = regexp.MustCompile(`^\t(.+):(\d+)(?: .*)?$`)
+	createdByPattern = regexp.MustCompile(`^created by (\S+)(?: in goroutine (\d+))?$`)
+)
+
+// ParsePanic returns an error rebuilt from the output of a Go panic,
+// so the crashes go through JSONWithStack and the printers as the other errors.
+// The lines before "panic: " such as the logs are skipped.
+//
+// The error of the panicking goroutine has the message "panic: ..." and its frames as the callers.
+// If the output has more goroutines, the error is a collection
+// of the panicking goroutine and the others with the messages "goroutine N [state]".
+// The goroutine ID, the state and the creator of each goroutine are the fields.
+// The frame of "created by" is the last caller.
+func ParsePanic(r io.Reader) (error, error) {
+	p := &panicParser{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		p.parseLine(strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return p.result()
+}
+
+func (p *panicParser) parseLine(line string) {
+	if strings.HasPrefix(line, panicPrefix) && len(p.goroutines) <= 0 {
+		p.message = []string{strings.TrimPrefix(line, panicPrefix)}
+		p.inMessage = true
+		return
+	}
+	if p.inMessage {
+		if line != "" && !goroutinePattern.MatchString(line) {
+			p.message = append(p.message, line)
+			return
+		}
+		p.inMessage = false
+	}
+
+	if match := goroutinePattern.FindStringSubmatch(line); match != nil {
+		id, _ := strconv.Atoi(match[1])
+		p.goroutines = append(p.goroutines, &parsedGoroutine{
+			id:    id,
+			state: match[2],
+		})
+		p.function = ""
+		return
+	}
+	if len(p.goroutines) <= 0 {
+		return
+	}
+
+	g := p.goroutines[len(p.goroutines)-1]
+	if match := createdByPattern.FindStringSubmatch(line); match != nil {
+		g.createdBy = strings.TrimPrefix(line, createdByPrefix)
+		p.function = match[1]
+		return
+	}
+	if match := functionPattern.FindStringSubmatch(line); match != nil {
+		p.function = match[1]
+		return
+	}
+	if match := filePattern.FindStringSubmatch(line); match != nil && p.function != "" {
+		lineNumber, _ := strconv.Atoi(match[2])
+		g.items = append(g.items, &callerInfoItem{
+			File:     TrimModulePath(match[1], p.function),
+			Line:     lineNumber,
+			Function: p.function,
+			path:     match[1],
+		})
+		p.function = ""
+		return
+	}
+	// the other lines such as "exit status 2" are skipped.
+	p.function = ""
+}
+
+func (p *panicParser) result() (error, error) {
+	if len(p.goroutines) <= 0 {
+		return nil, ErrNoGoroutine
+	}
+
+	errs := make([]error, len(p.goroutines))
+	for index, g := range p.goroutines {
+		e := g.toErrorType()
+		if index == 0 && p.message != nil {
+			e.msg = panicPrefix + strings.Join(p.message, "\n")
+		}
+		errs[index] = e.typed()
+	}
+	if len(errs) == 1 {
+		return errs[0], nil
+	}
+
+	c := newCollection()
+	for _, err := range errs {
+		c.append(err)
+	}
+	return c, nil
+}
+
+func (g *parsedGoroutine) toErrorType() *errorType {
+	fields := map[string]interface{}{
+		goroutineKey:      g.id,
+		goroutineStateKey: g.state,
+	}
+	if g.createdBy != "" {
+		fields[createdByKey] = g.createdBy
+	}
+	return &errorType{
+		msg:    fmt.Sprintf(goroutineFormat, g.id, g.state),
+		info:   resolvedCallerInfo(g.items),
+		fields: fields,
+	}
+}
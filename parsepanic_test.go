@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	capturedPanic = `2026/10/18 10:00:00 starting worker
+panic: runtime error: index out of range [5] with length 3
+
+goroutine 6 [running]:
+main.(*T).at(...)
+	/home/user/app/main.go:7
+main.worker(0x0?, 0x0?, 0x0?)
+	/home/user/app/main.go:11 +0x79
+created by main.main in goroutine 1
+	/home/user/app/main.go:20 +0x179
+
+goroutine 1 [runnable]:
+sync.runtime_SemacquireWaitGroup(0x11617b95c020?, 0xe0?)
+	/usr/local/go/src/runtime/sema.go:114 +0x2e
+sync.(*WaitGroup).Wait(0x11617b928120)
+	/usr/local/go/src/sync/waitgroup.go:206 +0x85
+main.main()
+	/home/user/app/main.go:21 +0x185
+
+goroutine 5 [runnable]:
+main.main.func1()
+	/home/user/app/main.go:19
+created by main.main in goroutine 1
+	/home/user/app/main.go:19 +0x10d
+exit status 2
+`
+
+	capturedRecoveredPanic = `panic: boom [recovered]
+	panic: boom again
+
+goroutine 1 [running]:
+main.main.func1()
+	/home/user/app/main.go:9 +0x54
+panic({0x4a1b20?, 0xc0000140a8?})
+	/usr/local/go/src/runtime/panic.go:785 +0x132
+main.main()
+	/home/user/app/main.go:12 +0x38
+`
+)
+
+func TestParsePanic(t *testing.T) {
+	err, parseErr := ParsePanic(strings.NewReader(capturedPanic))
+	if parseErr != nil {
+		t.Fatal("parse failed", parseErr)
+	}
+	c, ok := err.(*collection)
+	if !ok || len(c.errs) != 3 {
+		t.Fatal("invalid goroutines", err)
+	}
+
+	first := c.errs[0].(*leafError)
+	if first.Error() != "panic: runtime error: index out of range [5] with length 3" {
+		t.Fatal("invalid message", first.Error())
+	}
+	items := first.info.Items()
+	if len(items) != 3 {
+		t.Fatal("invalid frames", items)
+	}
+	if items[0].Function != "main.(*T).at" || items[0].Line != 7 || items[0].path != "/home/user/app/main.go" {
+		t.Fatal("invalid inlined frame", items[0])
+	}
+	if items[2].Function != "main.main" || items[2].Line != 20 {
+		t.Fatal("invalid created by frame", items[2])
+	}
+	if first.fields[goroutineKey] != 6 || first.fields[goroutineStateKey] != "running" || first.fields[createdByKey] != "main.main in goroutine 1" {
+		t.Fatal("invalid fields", first.fields)
+	}
+
+	second := c.errs[1].(*leafError)
+	if second.Error() != "goroutine 1 [runnable]" || len(second.info.Items()) != 3 {
+		t.Fatal("invalid second goroutine", second, second.info.Items())
+	}
+	if items := c.errs[2].(*leafError).info.Items(); len(items) != 2 || items[0].Function != "main.main.func1" {
+		t.Fatal("invalid third goroutine", items)
+	}
+
+	p := NewPrinter().WithRedactor(NewRuleRedactor()).WithPathTrimmer(func(path string, function string) string {
+		return path
+	})
+	s, _ := p.JSONWithStack(err, 10)
+	if strings.Contains(s, "/home/user") || !strings.Contains(s, `"file":"~/app/main.go"`) {
+		t.Fatal("not redacted", s)
+	}
+}
+
+func TestParseRecoveredPanic(t *testing.T) {
+	err, parseErr := ParsePanic(strings.NewReader(capturedRecoveredPanic))
+	if parseErr != nil {
+		t.Fatal("parse failed", parseErr)
+	}
+	e := err.(*leafError)
+	if e.Error() != "panic: boom [recovered]\n\tpanic: boom again" {
+		t.Fatal("invalid message", e.Error())
+	}
+	if items := e.info.Items(); len(items) != 3 || items[1].Function != "panic" {
+		t.Fatal("invalid frames", items)
+	}
+
+	if _, parseErr := ParsePanic(strings.NewReader("no panic\n")); parseErr != ErrNoGoroutine {
+		t.Fatal("invalid error", parseErr)
+	}
+}
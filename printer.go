@@ -0,0 +1,310 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+)
+
+type (
+	// PathTrimmer returns the file path to print
+	// from the path of the source file and the function name.
+	PathTrimmer func(path string, function string) string
+
+	// FrameFilter reports whether the frame should be printed.
+	FrameFilter func(function string, file string) bool
+
+	// Printer prints errors with the locations.
+	// Printer is immutable, the WithXxx methods return a new Printer.
+	// It is safe for concurrent use by multiple goroutines.
+	// The zero value prints with the default max stack and location format,
+	// but without the path trimming and the indent of NewPrinter.
+	Printer struct {
+		maxStack       int
+		locationFormat string
+		indent         string
+		trimPath       PathTrimmer
+		filters        []FrameFilter
+		redactor       Redactor
+		elideCommon    bool
+		panicStack     bool
+		captureStack   int
+	}
+)
+
+const (
+	locationFormat = "%s:%d:%s"
+
+	// filteredFramesMargin is the frame count captured for the filtered frames.
+	filteredFramesMargin = 32
+)
+
+var (
+	defaultPrinter atomic.Value
+)
+
+// NewPrinter returns a new Printer with the default settings.
+func NewPrinter() *Printer {
+	return &Printer{
+		maxStack:       CallerInfoMaxStack,
+		locationFormat: StringWithLocationFormat,
+		indent:         StringWithInnerIndent,
+		trimPath:       TrimModulePath,
+	}
+}
+
+// DefaultPrinter returns the printer used by the package level functions.
+func DefaultPrinter() *Printer {
+	if p, _ := defaultPrinter.Load().(*Printer); p != nil {
+		return p
+	}
+	return NewPrinter()
+}
+
+// SetDefaultPrinter replaces the printer used by the package level functions.
+// The max stack of the printer is also used to capture the callers by New and Wrap.
+// If the p is nil, the default settings are restored.
+func SetDefaultPrinter(p *Printer) {
+	defaultPrinter.Store(p)
+}
+
+// captureMaxStack returns the maximum stack count to capture
+// by the default printer.
+func captureMaxStack() int {
+	if p, _ := defaultPrinter.Load().(*Printer); p != nil {
+		return p.captureMaxStack()
+	}
+	return CallerInfoMaxStack
+}
+
+// captureMaxStack returns the maximum stack count to capture while the p is the default printer.
+// The filters are applied before the depth limit,
+// so the frames for the filtered frames are also captured if the p has filters.
+func (p *Printer) captureMaxStack() int {
+	n := p.stackCount()
+	if 0 < len(p.filters) {
+		n += filteredFramesMargin
+	}
+	if n < p.captureStack {
+		return p.captureStack
+	}
+	return n
+}
+
+// WithMaxStack returns a new Printer which prints n frames by JSONAll.
+// If n is 0 or less, CallerInfoMaxStack is used.
+// The errors capture the frames by the max stack of the default printer,
+// so the other printers can't print more frames than it
+// unless the default printer has a larger capture stack by WithCaptureStack.
+func (p *Printer) WithMaxStack(n int) *Printer {
+	result := p.clone()
+	result.maxStack = n
+	return result
+}
+
+// WithLocationFormat returns a new Printer
+// which formats the location and the message by the format.
+// If the format is empty, StringWithLocationFormat is used.
+func (p *Printer) WithLocationFormat(format string) *Printer {
+	result := p.clone()
+	result.locationFormat = format
+	return result
+}
+
+// WithIndent returns a new Printer which indents inner errors by the indent.
+func (p *Printer) WithIndent(indent string) *Printer {
+	result := p.clone()
+	result.indent = indent
+	return result
+}
+
+// WithPathTrimmer returns a new Printer which trims file paths by the trimmer.
+func (p *Printer) WithPathTrimmer(trimmer PathTrimmer) *Printer {
+	result := p.clone()
+	result.trimPath = trimmer
+	return result
+}
+
+// WithFrameFilter returns a new Printer which prints only the frames
+// accepted by all the filters, such as AppOnlyFrames and NoRuntimeFrames.
+// The filters are applied before the max stack is counted.
+// If the default printer has filters, the errors capture its max stack and 32 more frames,
+// so at most 32 frames can be filtered out without losing the frames to print.
+// The errors capture no more frames for the filters of the other printers
+// unless the default printer has a larger capture stack by WithCaptureStack.
+func (p *Printer) WithFrameFilter(filters ...FrameFilter) *Printer {
+	result := p.clone()
+	result.filters = append(append([]FrameFilter{}, p.filters...), filters...)
+	return result
+}
+
+// WithCaptureStack returns a new Printer which makes New and Wrap capture
+// at least n frames while it is the default printer.
+// It is for the other printers with the larger max stack or the frame filters.
+// Capturing more frames makes New and Wrap slower.
+func (p *Printer) WithCaptureStack(n int) *Printer {
+	result := p.clone()
+	result.captureStack = n
+	return result
+}
+
+// JSON returns a json string which has error trace.
+func (p *Printer) JSON(err error) (string, error) {
+	return p.JSONWithStack(err, 1)
+}
+
+// JSONAll returns a json string which has error trace.
+func (p *Printer) JSONAll(err error) (string, error) {
+	return p.JSONWithStack(err, p.stackCount())
+}
+
+// JSONWithStack returns a json string which has error trace.
+func (p *Printer) JSONWithStack(err error, stackCount int) (string, error) {
+	if err == nil {
+		return "", nil
+	}
+
+	b, e := json.Marshal(newErrMarshal(p, err, stackCount))
+	return string(b), e
+}
+
+// StringWithLocation returns error location and error message as string.
+func (p *Printer) StringWithLocation(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	if e, ok := errorTypeOf(err); ok {
+		return fmt.Sprintf(p.lineFormat(), p.location(e.info), p.redactMessage(e.Error()))
+	}
+	if e, ok := err.(*errorSource); ok {
+		return fmt.Sprintf(p.lineFormat(), p.location(e.info), p.redactMessage(e.Error()))
+	}
+	if c, ok := err.(*collection); ok {
+		return c.stringWithLocation(p)
+	}
+	if info := foreignStackOf(err); info != nil {
+		return fmt.Sprintf(p.lineFormat(), p.location(info), p.redactMessage(err.Error()))
+	}
+	return p.redactMessage(err.Error())
+}
+
+// StringWithInner returns string representation of the error and inner errors.
+func (p *Printer) StringWithInner(err error) string {
+	if err == nil {
+		return ""
+	}
+	return p.stringWithInner(err, "")
+}
+
+func (p *Printer) stringWithInner(err error, indent string) string {
+	buf := &bytes.Buffer{}
+
+	if e, ok := errorTypeOf(err); ok {
+		fmt.Fprintln(buf, indent+p.StringWithLocation(err)+fieldsString(p.redactFields(e.fields)))
+		p.writeLayerFrames(buf, e.info, e.inner, indent)
+		if e.inner != nil {
+			fmt.Fprint(buf, p.stringWithInner(e.inner, indent+p.indent))
+		}
+		return buf.String()
+	}
+	if e, ok := err.(*errorSource); ok {
+		fmt.Fprintln(buf, indent+p.StringWithLocation(err)+fieldsString(p.redactFields(e.fields)))
+		p.writeLayerFrames(buf, e.info, e.inner, indent)
+		if e.inner != nil {
+			fmt.Fprint(buf, p.stringWithInner(e.inner, indent+p.indent))
+		}
+		return buf.String()
+	}
+
+	if c, ok := err.(*collection); ok {
+		fmt.Fprint(buf, c.stringWithInner(p, indent+p.indent))
+		return buf.String()
+	}
+
+	if inners := unwrapForeign(err); inners != nil {
+		fmt.Fprintln(buf, indent+fmt.Sprintf(foreignLayerFormat, p.StringWithLocation(err), typeNameOf(err)))
+		for _, inner := range inners {
+			fmt.Fprint(buf, p.stringWithInner(inner, indent+p.indent))
+		}
+		return buf.String()
+	}
+
+	fmt.Fprintln(buf, indent+p.StringWithLocation(err))
+	return buf.String()
+}
+
+// location returns the first frame of the info as string.
+func (p *Printer) location(info *callerInfo) string {
+	frames := p.frames(info, 1)
+	if len(frames) <= 0 {
+		return ""
+	}
+
+	first := frames[0]
+	return fmt.Sprintf(locationFormat, first.File, first.Line, first.Function)
+}
+
+// frames returns at most n frames of the info
+// which are accepted by the filters and have trimmed file paths.
+func (p *Printer) frames(info *callerInfo, n int) []*callerInfoItem {
+	result := []*callerInfoItem{}
+	if info == nil {
+		return result
+	}
+
+	for _, item := range info.Items() {
+		if n <= len(result) {
+			break
+		}
+		file := p.trim(item)
+		if !p.accept(item.Function, file) {
+			continue
+		}
+		result = append(result, &callerInfoItem{
+			File:     p.redactPath(file),
+			Line:     item.Line,
+			Function: item.Function,
+			path:     item.path,
+		})
+	}
+	return result
+}
+
+// stackCount returns the max stack of the p, or CallerInfoMaxStack for the zero value.
+func (p *Printer) stackCount() int {
+	if p.maxStack <= 0 {
+		return CallerInfoMaxStack
+	}
+	return p.maxStack
+}
+
+// lineFormat returns the location format of the p, or StringWithLocationFormat for the zero value.
+func (p *Printer) lineFormat() string {
+	if p.locationFormat == "" {
+		return StringWithLocationFormat
+	}
+	return p.locationFormat
+}
+
+func (p *Printer) trim(item *callerInfoItem) string {
+	if item.path == "" || p.trimPath == nil {
+		return item.File
+	}
+	return p.trimPath(item.path, item.Function)
+}
+
+func (p *Printer) accept(function string, file string) bool {
+	for _, filter := range p.filters {
+		if !filter(function, file) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *Printer) clone() *Printer {
+	result := *p
+	return &result
+}
@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPrinter(t *testing.T) {
+	inner := New("inner")
+	outer := Wrap(inner, "outer")
+
+	p := NewPrinter().
+		WithLocationFormat("%[2]s at %[1]s").
+		WithIndent("  ").
+		WithPathTrimmer(func(path string, function string) string {
+			return filepath.Base(path)
+		})
+
+	s := p.StringWithLocation(outer)
+	if !strings.HasPrefix(s, "outer at printer_test.go:") {
+		t.Fatal("invalid string with location", s)
+	}
+	lines := strings.Split(p.StringWithInner(outer), "\n")
+	if !strings.HasPrefix(lines[1], "  inner at printer_test.go:") {
+		t.Fatal("invalid string with inner", lines)
+	}
+
+	if StringWithLocation(outer) == s {
+		t.Fatal("default printer is changed", StringWithLocation(outer))
+	}
+}
+
+func TestPrinterJSON(t *testing.T) {
+	e := New("error")
+
+	p := NewPrinter().WithMaxStack(2)
+	s, _ := p.JSONAll(e)
+	obj := struct {
+		Callers []*callerInfoItem `json:"callers"`
+	}{}
+	json.Unmarshal([]byte(s), &obj)
+	if len(obj.Callers) != 2 {
+		t.Fatal("invalid callers count", s)
+	}
+
+	p = p.WithFrameFilter(func(function string, file string) bool {
+		return !strings.HasPrefix(function, "github.com/trimark-jp/errors.")
+	})
+	s, _ = p.JSONAll(e)
+	json.Unmarshal([]byte(s), &obj)
+	if obj.Callers[0].Function != "testing.tRunner" {
+		t.Fatal("frames not filtered", s)
+	}
+}
+
+func TestZeroPrinter(t *testing.T) {
+	e := New("error")
+	p := &Printer{}
+
+	if s := p.StringWithLocation(e); strings.Contains(s, "%!") || !strings.HasSuffix(s, "TestZeroPrinter\terror") {
+		t.Fatal("invalid string with location", s)
+	}
+
+	s, _ := p.JSONAll(e)
+	obj := struct {
+		Callers []*callerInfoItem `json:"callers"`
+	}{}
+	json.Unmarshal([]byte(s), &obj)
+	if len(obj.Callers) == 0 {
+		t.Fatal("no callers", s)
+	}
+}
+
+func TestSetDefaultPrinter(t *testing.T) {
+	defer SetDefaultPrinter(nil)
+
+	e := New("error")
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			SetDefaultPrinter(NewPrinter().WithLocationFormat("%[2]s"))
+		}()
+		go func() {
+			defer wg.Done()
+			StringWithLocation(e)
+			JSONAll(e)
+		}()
+	}
+	wg.Wait()
+
+	if s := StringWithLocation(e); s != "error" {
+		t.Fatal("default printer not replaced", s)
+	}
+
+	SetDefaultPrinter(nil)
+	if s := StringWithLocation(e); s == "error" {
+		t.Fatal("default printer not restored", s)
+	}
+}
@@ -0,0 +1,305 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+type (
+	// ProblemDetails is a problem details object of RFC 9457 (RFC 7807).
+	// It implements error and HTTPStatuser,
+	// so the parsed problem works as the source error.
+	ProblemDetails struct {
+		Type     string `json:"type,omitempty"`
+		Title    string `json:"title,omitempty"`
+		Status   int    `json:"status,omitempty"`
+		Detail   string `json:"detail,omitempty"`
+		Instance string `json:"instance,omitempty"`
+
+		// Errors has a problem for each error of a collection.
+		Errors []*ProblemDetails `json:"errors,omitempty"`
+
+		// Trace is the output of JSONWithStack in the debug mode.
+		Trace json.RawMessage `json:"trace,omitempty"`
+
+		// Extensions are the extension members from the fields of the error
+		// which are listed in ProblemBuilder.ExtensionFields.
+		Extensions map[string]interface{} `json:"-"`
+	}
+
+	// ProblemBuilder builds problem details from errors.
+	// The zero value is ready to use.
+	ProblemBuilder struct {
+		// TypeBase is the prefix of the type URI.
+		// The type is TypeBase + code for the errors with a code,
+		// and "about:blank" for the others.
+		TypeBase string
+
+		// Debug embeds the trace of the error.
+		// It must not be enabled for the public APIs.
+		Debug bool
+
+		// StackCount is the frame count of the trace.
+		// If it is zero, the max stack of the default printer is used.
+		StackCount int
+
+		// ExtensionFields are the keys of the fields written as the extension members.
+		// The other fields are internal and not written.
+		// The values are redacted by the redactor of the default printer.
+		ExtensionFields []string
+	}
+
+	// problemMembers is ProblemDetails without the methods.
+	problemMembers ProblemDetails
+)
+
+const (
+	// ProblemContentType is the media type of the problem details.
+	ProblemContentType = "application/problem+json"
+
+	problemBlankType = "about:blank"
+)
+
+var (
+	problemReservedMembers = map[string]bool{
+		"type":     true,
+		"title":    true,
+		"status":   true,
+		"detail":   true,
+		"instance": true,
+		"errors":   true,
+		"trace":    true,
+	}
+)
+
+// Problem returns the problem details of the err by a zero ProblemBuilder.
+func Problem(err error) *ProblemDetails {
+	return (&ProblemBuilder{}).Problem(err)
+}
+
+// Problem returns the problem details of the err.
+// The status is the one HTTPResponder writes,
+// the detail is the public message or the message of the explicit source of the 4xx errors,
+// the title is the registered message of the code or the status text,
+// and the extension members are the fields of the err listed in the ExtensionFields.
+// Returns nil if the err is nil.
+func (b *ProblemBuilder) Problem(err error) *ProblemDetails {
+	return b.build(err, &HTTPResponder{})
+}
+
+// build returns the problem details of the err
+// with the status and the detail which the h writes.
+func (b *ProblemBuilder) build(err error, h *HTTPResponder) *ProblemDetails {
+	if err == nil {
+		return nil
+	}
+
+	p := b.problem(err, h)
+	if c, ok := err.(*collection); ok {
+		for _, e := range c.errs {
+			p.Errors = append(p.Errors, b.problem(e, h))
+		}
+	}
+	if b.Debug {
+		stackCount := b.StackCount
+		if stackCount == 0 {
+			stackCount = DefaultPrinter().stackCount()
+		}
+		if trace, traceErr := JSONWithStack(err, stackCount); traceErr == nil {
+			p.Trace = json.RawMessage(trace)
+		}
+	}
+	return p
+}
+
+func (b *ProblemBuilder) problem(err error, h *HTTPResponder) *ProblemDetails {
+	status, _ := h.Response(err)
+	p := &ProblemDetails{
+		Type:   problemBlankType,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: problemDetail(err, status),
+	}
+	if p.Detail == p.Title {
+		p.Detail = ""
+	}
+
+	if code := CodeOf(err); code != "" {
+		p.Type = b.TypeBase + string(code)
+		if info, ok := LookupCode(code); ok && info.Message != "" {
+			p.Title = info.Message
+		}
+	}
+
+	p.Extensions = b.extensions(err)
+	return p
+}
+
+// extensions returns the redacted fields of the err listed in the ExtensionFields.
+func (b *ProblemBuilder) extensions(err error) map[string]interface{} {
+	if len(b.ExtensionFields) <= 0 {
+		return nil
+	}
+
+	fields := Fields(err)
+	var result map[string]interface{}
+	for _, key := range b.ExtensionFields {
+		value, ok := fields[key]
+		if !ok || problemReservedMembers[key] {
+			continue
+		}
+		if result == nil {
+			result = map[string]interface{}{}
+		}
+		result[key] = value
+	}
+	return DefaultPrinter().redactFields(result)
+}
+
+// problemDetail returns the public message of the err,
+// or the message of the explicit source for the 4xx errors.
+// Returns "" for the other errors.
+func problemDetail(err error, status int) string {
+	if HasPublicMessage(err) {
+		return PublicMessage(err)
+	}
+	if http.StatusInternalServerError <= status {
+		return ""
+	}
+	if s := ExplicitSourceOf(err); s != nil {
+		return s.Error()
+	}
+	return ""
+}
+
+// Error implements error interface.
+func (p *ProblemDetails) Error() string {
+	if p.Detail != "" {
+		return p.Detail
+	}
+	return p.Title
+}
+
+// PublicMessage implements PublicMessager interface.
+func (p *ProblemDetails) PublicMessage() string {
+	return p.Error()
+}
+
+// HTTPStatus implements HTTPStatuser interface.
+func (p *ProblemDetails) HTTPStatus() int {
+	return p.Status
+}
+
+// MarshalJSON implements json.Marshaler interface.
+// The extension members are marshalled as the top level members.
+func (p *ProblemDetails) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal((*problemMembers)(p))
+	if err != nil || len(p.Extensions) <= 0 {
+		return b, err
+	}
+
+	keys := make([]string, 0, len(p.Extensions))
+	for key := range p.Extensions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	buf := bytes.NewBuffer(b[:len(b)-1])
+	for _, key := range keys {
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(p.Extensions[key])
+		if err != nil {
+			return nil, err
+		}
+		if 1 < buf.Len() {
+			buf.WriteByte(',')
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+// The unknown members are unmarshalled as the extension members.
+func (p *ProblemDetails) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, (*problemMembers)(p)); err != nil {
+		return err
+	}
+
+	members := map[string]interface{}{}
+	if err := json.Unmarshal(b, &members); err != nil {
+		return err
+	}
+	for key, value := range members {
+		if problemReservedMembers[key] {
+			continue
+		}
+		if p.Extensions == nil {
+			p.Extensions = map[string]interface{}{}
+		}
+		p.Extensions[key] = value
+	}
+	return nil
+}
+
+// ParseProblem returns an error rebuilt from the problem details.
+// SourceOf the error returns the *ProblemDetails.
+// The extension members become the fields,
+// the last segment of the type becomes the code,
+// and the trace or the errors become the inner error.
+func ParseProblem(b []byte) (error, error) {
+	p := &ProblemDetails{}
+	if err := json.Unmarshal(b, p); err != nil {
+		return nil, err
+	}
+	return p.toError()
+}
+
+func (p *ProblemDetails) toError() (error, error) {
+	var inner error
+	if len(p.Trace) != 0 {
+		trace, err := unmarshalError(p.Trace)
+		if err != nil {
+			return nil, err
+		}
+		inner = trace
+	} else if 0 < len(p.Errors) {
+		c := newCollection()
+		for _, e := range p.Errors {
+			err, parseErr := e.toError()
+			if parseErr != nil {
+				return nil, parseErr
+			}
+			c.append(err)
+		}
+		inner = c
+	}
+
+	e := &errorType{
+		inner:  inner,
+		info:   resolvedCallerInfo(nil),
+		fields: copyFields(p.Extensions),
+		code:   p.code(),
+	}
+	return &errorSource{
+		errorType: e,
+		source:    p,
+	}, nil
+}
+
+// code returns the last segment of the type.
+func (p *ProblemDetails) code() Code {
+	if p.Type == "" || p.Type == problemBlankType {
+		return ""
+	}
+	return Code(p.Type[strings.LastIndex(p.Type, "/")+1:])
+}
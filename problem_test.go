@@ -0,0 +1,140 @@
+package errors
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestProblem(t *testing.T) {
+	e := Wrap(With(WrapBySourceMsg(NewCode(codeTestNotFound, ""), "user 10 not found"), "userID", 10), "lookup failed")
+
+	b := &ProblemBuilder{TypeBase: "https://example.com/problems/", ExtensionFields: []string{"userID"}}
+	p := b.Problem(e)
+	if p.Type != "https://example.com/problems/test_not_found" {
+		t.Fatal("invalid type", p.Type)
+	}
+	if p.Title != "not found" || p.Status != http.StatusNotFound || p.Detail != "user 10 not found" {
+		t.Fatal("invalid problem", p)
+	}
+	if p.Trace != nil {
+		t.Fatal("trace embedded without debug", string(p.Trace))
+	}
+
+	s, _ := json.Marshal(p)
+	expected := `{"type":"https://example.com/problems/test_not_found","title":"not found","status":404,"detail":"user 10 not found","userID":10}`
+	if string(s) != expected {
+		t.Fatal("invalid json", string(s))
+	}
+
+	parsed, err := ParseProblem(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source, ok := SourceOf(parsed).(*ProblemDetails)
+	if !ok || source.Status != http.StatusNotFound {
+		t.Fatal("invalid source", SourceOf(parsed))
+	}
+	if CodeOf(parsed) != codeTestNotFound || Fields(parsed)["userID"] != float64(10) {
+		t.Fatal("invalid code or fields", CodeOf(parsed), Fields(parsed))
+	}
+	if HTTPStatusOf(parsed) != http.StatusNotFound {
+		t.Fatal("invalid status", HTTPStatusOf(parsed))
+	}
+}
+
+func TestProblemOfCollection(t *testing.T) {
+	c := Merge(WrapBySourceMsg(NewCode(codeTestInvalid, ""), "name is required"), New("internal"))
+	p := Problem(c)
+	if p.Status != http.StatusBadRequest || len(p.Errors) != 2 {
+		t.Fatal("invalid problem", p)
+	}
+	if p.Errors[0].Detail != "name is required" || p.Errors[1].Status != http.StatusInternalServerError {
+		t.Fatal("invalid errors", p.Errors[0], p.Errors[1])
+	}
+	if p.Errors[1].Detail != "" {
+		t.Fatal("internal message leaked", p.Errors[1].Detail)
+	}
+
+	s, _ := json.Marshal(p)
+	parsed, _ := ParseProblem(s)
+	inner := parsed.(*errorSource).inner.(*collection)
+	if len(inner.errs) != 2 {
+		t.Fatal("errors not parsed", parsed)
+	}
+}
+
+func TestProblemImplicitSource(t *testing.T) {
+	p := Problem(Merge(New("db dsn user:pw@10.0.0.5"), NewCode(codeTestInvalid, "bad")))
+	if p.Status != http.StatusBadRequest || p.Detail != "" {
+		t.Fatal("implicit source written", p)
+	}
+	if p.Errors[0].Detail != "" || p.Errors[1].Detail != "" {
+		t.Fatal("implicit source written", p.Errors[0], p.Errors[1])
+	}
+
+	p = Problem(WrapByCode(stderrors.New("pq: password=hunter2"), codeTestNotFound, ""))
+	if p.Title != "not found" || p.Detail != "" {
+		t.Fatal("implicit source written", p)
+	}
+}
+
+func TestProblemExtensions(t *testing.T) {
+	defer SetDefaultPrinter(nil)
+	SetDefaultPrinter(NewPrinter().WithRedactor(NewRuleRedactor()))
+
+	e := With(NewCode(codeTestInvalid, ""), "password", "hunter2", "userID", 10, "shard", "users_3")
+	if p := Problem(e); p.Extensions != nil {
+		t.Fatal("fields written without ExtensionFields", p.Extensions)
+	}
+
+	p := (&ProblemBuilder{ExtensionFields: []string{"password", "userID"}}).Problem(e)
+	if p.Extensions["password"] != DefaultRedactReplacement || p.Extensions["userID"] != 10 {
+		t.Fatal("invalid extensions", p.Extensions)
+	}
+	if _, ok := p.Extensions["shard"]; ok {
+		t.Fatal("unlisted field written", p.Extensions)
+	}
+}
+
+func TestProblemDebug(t *testing.T) {
+	e := Wrap(io.EOF, "read failed")
+	p := (&ProblemBuilder{Debug: true}).Problem(e)
+	trace, _ := JSONAll(e)
+	if string(p.Trace) != trace {
+		t.Fatal("invalid trace", string(p.Trace))
+	}
+
+	s, _ := json.Marshal(p)
+	parsed, _ := ParseProblem(s)
+	if !strings.Contains(StringWithInner(parsed), "read failed") {
+		t.Fatal("trace not parsed", StringWithInner(parsed))
+	}
+}
+
+func TestHTTPResponderProblem(t *testing.T) {
+	h := &HTTPResponder{
+		Problem: &ProblemBuilder{},
+	}
+	handler := h.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return NewCode(codeTestInvalid, "")
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/users?id=x", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatal("invalid status", w.Code)
+	}
+	if w.Header().Get("Content-Type") != ProblemContentType {
+		t.Fatal("invalid content type", w.Header().Get("Content-Type"))
+	}
+	p := &ProblemDetails{}
+	json.Unmarshal(w.Body.Bytes(), p)
+	if p.Instance != "/users?id=x" || p.Type != "test_invalid" {
+		t.Fatal("invalid problem", w.Body.String())
+	}
+}
@@ -0,0 +1,117 @@
+package errors
+
+import "encoding/json"
+
+type (
+	// PublicMessager is implemented by the errors
+	// which have the message to show to the users.
+	PublicMessager interface {
+		PublicMessage() string
+	}
+)
+
+const (
+	// DefaultPublicMessage is the public message of the errors
+	// which have no public message.
+	DefaultPublicMessage = "internal error"
+)
+
+// NewPublic returns a new source error which has the public message.
+func NewPublic(publicMsg string) error {
+	return newPublic(nil, publicMsg, 1)
+}
+
+// WrapPublic returns a new source error which has the public message.
+// The public message is returned by PublicMessage,
+// and the inner errors are kept for the logs.
+func WrapPublic(inner error, publicMsg string) error {
+	if inner == nil {
+		return nil
+	}
+	return newPublic(inner, publicMsg, 1)
+}
+
+// PublicMessage returns the public message of the err.
+// Like ExplicitSourceOf, the public message of the outer error wins,
+// and the errors in a collection are searched in order.
+// Returns DefaultPublicMessage if no public message is found.
+func PublicMessage(err error) string {
+	if msg := publicMessageOf(err); msg != "" {
+		return msg
+	}
+	return DefaultPublicMessage
+}
+
+// HasPublicMessage reports whether the err has a public message.
+func HasPublicMessage(err error) bool {
+	return publicMessageOf(err) != ""
+}
+
+// PublicJSON returns a json string which has only the public data,
+// the public message and the code.
+func PublicJSON(err error) (string, error) {
+	if err == nil {
+		return "", nil
+	}
+	b, e := json.Marshal(publicObject(err))
+	return string(b), e
+}
+
+func publicObject(err error) interface{} {
+	if c, ok := err.(*collection); ok {
+		obj := struct {
+			Errs []interface{} `json:"errors"`
+		}{
+			Errs: make([]interface{}, len(c.errs)),
+		}
+		for index, e := range c.errs {
+			obj.Errs[index] = publicObject(e)
+		}
+		return &obj
+	}
+	return &struct {
+		Message string `json:"message"`
+		Code    Code   `json:"code,omitempty"`
+	}{
+		Message: PublicMessage(err),
+		Code:    CodeOf(err),
+	}
+}
+
+func publicMessageOf(err error) string {
+	if e, ok := err.(*errorSource); ok {
+		if e.public != "" {
+			return e.public
+		}
+		if msg := publicMessageOf(e.source); msg != "" {
+			return msg
+		}
+		return publicMessageOf(e.inner)
+	}
+	if c, ok := err.(*collection); ok {
+		for _, e := range c.errs {
+			if msg := publicMessageOf(e); msg != "" {
+				return msg
+			}
+		}
+		return ""
+	}
+	if e, ok := errorTypeOf(err); ok {
+		return publicMessageOf(e.inner)
+	}
+	if m, ok := err.(PublicMessager); ok {
+		return m.PublicMessage()
+	}
+	for _, inner := range unwrapForeign(err) {
+		if msg := publicMessageOf(inner); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+func newPublic(inner error, publicMsg string, skip int) error {
+	e := newSource(inner, new(nil, publicMsg, skip+1), skip+1).(*errorSource)
+	e.public = publicMsg
+	return e
+}
@@ -0,0 +1,75 @@
+package errors
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPublicMessage(t *testing.T) {
+	const (
+		publicMessage = "please try again"
+	)
+	inner := Wrap(io.ErrUnexpectedEOF, "password=secret")
+	e := Wrap(WrapPublic(inner, publicMessage), "outer")
+
+	if PublicMessage(e) != publicMessage {
+		t.Fatal("invalid public message", PublicMessage(e))
+	}
+	if SourceOf(e).Error() != publicMessage {
+		t.Fatal("invalid source", SourceOf(e))
+	}
+	if PublicMessage(inner) != DefaultPublicMessage || HasPublicMessage(inner) {
+		t.Fatal("invalid fallback", PublicMessage(inner))
+	}
+	if WrapPublic(nil, publicMessage) != nil {
+		t.Fatal("wrapped nil")
+	}
+
+	outer := WrapPublic(e, "outer public")
+	if PublicMessage(outer) != "outer public" {
+		t.Fatal("outer public message not preferred", PublicMessage(outer))
+	}
+	c := Merge(inner, NewPublic("merged public"))
+	if PublicMessage(c) != "merged public" {
+		t.Fatal("invalid public message of collection", PublicMessage(c))
+	}
+}
+
+func TestPublicJSON(t *testing.T) {
+	e := Wrap(WrapPublic(Wrap(io.EOF, "password=secret"), "please try again"), "outer")
+	s, _ := PublicJSON(e)
+	if s != `{"message":"please try again"}` {
+		t.Fatal("invalid public json", s)
+	}
+
+	c := Merge(e, NewCode(codeTestInvalid, "token=secret"))
+	s, _ = PublicJSON(c)
+	if s != `{"errors":[{"message":"please try again"},{"message":"internal error","code":"test_invalid"}]}` {
+		t.Fatal("invalid public json", s)
+	}
+
+	all, _ := JSONAll(e)
+	parsed, _ := ParseJSON(all)
+	if PublicMessage(parsed) != "please try again" {
+		t.Fatal("public message not parsed", all)
+	}
+}
+
+func TestHTTPResponderPublic(t *testing.T) {
+	h := &HTTPResponder{}
+	handler := h.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return WrapPublic(New("connection refused 10.0.0.1"), "service unavailable")
+	})
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatal("invalid status", w.Code)
+	}
+	if w.Body.String() != "service unavailable" || strings.Contains(w.Body.String(), "10.0.0.1") {
+		t.Fatal("invalid body", w.Body.String())
+	}
+}
@@ -0,0 +1,158 @@
+package errors
+
+import (
+	"regexp"
+	"strings"
+)
+
+type (
+	// Redactor removes sensitive data from the printed errors.
+	// It is applied by the Printer to the messages, the fields and the file paths.
+	Redactor interface {
+		RedactMessage(msg string) string
+		RedactField(key string, value interface{}) interface{}
+		RedactPath(path string) string
+	}
+
+	// RuleRedactor is a Redactor by the regular expressions,
+	// the denied field names and the path anonymisation.
+	RuleRedactor struct {
+		// Patterns are replaced with Replacement in the messages and the string fields.
+		// The submatch named keep is kept before Replacement,
+		// such as the parameter name of `(?P<keep>password=)\S+`.
+		Patterns []*regexp.Regexp
+
+		// DenyFields are the field names whose values are replaced with Replacement.
+		// The names are compared case-insensitively.
+		DenyFields []string
+
+		// AnonymizePaths replaces the home directories in the paths and the messages with "~".
+		AnonymizePaths bool
+
+		// Replacement replaces the sensitive data.
+		// If it is empty, DefaultRedactReplacement is used.
+		Replacement string
+	}
+)
+
+const (
+	// DefaultRedactReplacement is the default replacement of RuleRedactor.
+	DefaultRedactReplacement = "[REDACTED]"
+
+	homeReplacement = "~/"
+	redactKeepName  = "keep"
+)
+
+var (
+	// defaultRedactPatterns are the patterns of emails, bearer tokens and secret parameters.
+	defaultRedactPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+		regexp.MustCompile(`(?i)bearer\s+[A-Za-z0-9\-._~+/]+=*`),
+		regexp.MustCompile(`(?i)(?P<keep>(?:password|passwd|secret|token|api_?key)=)[^\s&"]+`),
+	}
+
+	// defaultDenyFields are the field names of secrets.
+	defaultDenyFields = []string{
+		"password", "passwd", "secret", "token", "apiKey", "api_key", "authorization", "cookie",
+	}
+
+	homePattern = regexp.MustCompile(`(?:/home/|/Users/|[A-Za-z]:[/\\]Users[/\\])[^/\\\s]+[/\\]`)
+)
+
+// NewRuleRedactor returns a new RuleRedactor with the path anonymisation,
+// the patterns of emails, bearer tokens and secret parameters such as password=...,
+// and the deny fields such as password, token and authorization.
+func NewRuleRedactor() *RuleRedactor {
+	return &RuleRedactor{
+		Patterns:       append([]*regexp.Regexp{}, defaultRedactPatterns...),
+		DenyFields:     append([]string{}, defaultDenyFields...),
+		AnonymizePaths: true,
+	}
+}
+
+// RedactMessage implements Redactor interface.
+func (r *RuleRedactor) RedactMessage(msg string) string {
+	for _, pattern := range r.Patterns {
+		msg = pattern.ReplaceAllString(msg, r.template(pattern))
+	}
+	if r.AnonymizePaths {
+		msg = homePattern.ReplaceAllString(msg, homeReplacement)
+	}
+	return msg
+}
+
+// RedactField implements Redactor interface.
+func (r *RuleRedactor) RedactField(key string, value interface{}) interface{} {
+	for _, denied := range r.DenyFields {
+		if strings.EqualFold(key, denied) {
+			return r.replacement()
+		}
+	}
+	if s, ok := value.(string); ok {
+		return r.RedactMessage(s)
+	}
+	return value
+}
+
+// RedactPath implements Redactor interface.
+func (r *RuleRedactor) RedactPath(path string) string {
+	if !r.AnonymizePaths {
+		return path
+	}
+	return homePattern.ReplaceAllString(path, homeReplacement)
+}
+
+// template returns the template of ReplaceAllString for the pattern
+// which keeps the submatch named keep.
+func (r *RuleRedactor) template(pattern *regexp.Regexp) string {
+	replacement := strings.ReplaceAll(r.replacement(), "$", "$$")
+	if pattern.SubexpIndex(redactKeepName) < 0 {
+		return replacement
+	}
+	return "${" + redactKeepName + "}" + replacement
+}
+
+func (r *RuleRedactor) replacement() string {
+	if r.Replacement == "" {
+		return DefaultRedactReplacement
+	}
+	return r.Replacement
+}
+
+// WithRedactor returns a new Printer which redacts by the r.
+// If the r is nil, nothing is redacted.
+func (p *Printer) WithRedactor(r Redactor) *Printer {
+	result := p.clone()
+	result.redactor = r
+	return result
+}
+
+func (p *Printer) redactMessage(msg string) string {
+	if !p.redacts() {
+		return msg
+	}
+	return p.redactor.RedactMessage(msg)
+}
+
+func (p *Printer) redactPath(path string) string {
+	if !p.redacts() {
+		return path
+	}
+	return p.redactor.RedactPath(path)
+}
+
+func (p *Printer) redactFields(fields map[string]interface{}) map[string]interface{} {
+	if !p.redacts() || len(fields) <= 0 {
+		return fields
+	}
+	result := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		result[key] = p.redactor.RedactField(key, value)
+	}
+	return result
+}
+
+// redacts returns true if the p has a redactor.
+func (p *Printer) redacts() bool {
+	return p != nil && p.redactor != nil
+}
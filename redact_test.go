@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRuleRedactor(t *testing.T) {
+	r := NewRuleRedactor()
+
+	msg := r.RedactMessage("login alice@example.com password=hunter2&next=/ Bearer abc.def")
+	if strings.Contains(msg, "alice@example.com") || strings.Contains(msg, "hunter2") || strings.Contains(msg, "abc.def") {
+		t.Fatal("not redacted", msg)
+	}
+	if !strings.Contains(msg, "password="+DefaultRedactReplacement) || !strings.Contains(msg, "next=/") {
+		t.Fatal("invalid redaction", msg)
+	}
+
+	if r.RedactField("Password", "hunter2") != DefaultRedactReplacement {
+		t.Fatal("deny field not redacted")
+	}
+	if r.RedactField("userID", 10) != 10 {
+		t.Fatal("field redacted")
+	}
+	if path := r.RedactPath("/home/alice/src/main.go"); path != "~/src/main.go" {
+		t.Fatal("path not anonymised", path)
+	}
+
+	r.Replacement = "***"
+	r.AnonymizePaths = false
+	if msg := r.RedactMessage("token=abc"); msg != "token=***" {
+		t.Fatal("invalid replacement", msg)
+	}
+	if path := r.RedactPath("/home/alice/src/main.go"); path != "/home/alice/src/main.go" {
+		t.Fatal("path anonymised", path)
+	}
+}
+
+func TestPrinterWithRedactor(t *testing.T) {
+	e := Wrap(With(New("user alice@example.com"), "token", "secret-token", "userID", 10), "outer")
+	p := NewPrinter().WithRedactor(NewRuleRedactor()).WithPathTrimmer(func(path, function string) string {
+		return "/home/alice/src/main.go"
+	})
+
+	s := p.StringWithInner(e)
+	if strings.Contains(s, "alice") || strings.Contains(s, "secret-token") {
+		t.Fatal("not redacted", s)
+	}
+	if !strings.Contains(s, "~/src/main.go") || !strings.Contains(s, "userID=10") {
+		t.Fatal("invalid string", s)
+	}
+
+	j, _ := p.JSONAll(e)
+	if strings.Contains(j, "alice") || strings.Contains(j, "secret-token") {
+		t.Fatal("not redacted", j)
+	}
+
+	if s := NewPrinter().StringWithInner(e); !strings.Contains(s, "alice@example.com") {
+		t.Fatal("redacted without redactor", s)
+	}
+}
+
+func TestDefaultPrinterRedactsFormatAndSlog(t *testing.T) {
+	defer SetDefaultPrinter(nil)
+	SetDefaultPrinter(NewPrinter().WithRedactor(NewRuleRedactor()))
+	e := Wrap(With(New("user alice@example.com"), "token", "secret-token"), "outer")
+
+	for _, s := range []string{fmt.Sprintf("%v", e), fmt.Sprintf("%s", e), fmt.Sprintf("%q", e), fmt.Sprintf("%+v", e), fmt.Sprintf("%#v", e)} {
+		if strings.Contains(s, "alice@example.com") {
+			t.Fatal("not redacted", s)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	slog.New(slog.NewJSONHandler(buf, nil)).Error("failed", slog.Any("err", e))
+	if strings.Contains(buf.String(), "alice@example.com") || strings.Contains(buf.String(), "secret-token") {
+		t.Fatal("not redacted", buf.String())
+	}
+	if !strings.Contains(buf.String(), DefaultRedactReplacement) {
+		t.Fatal("invalid log", buf.String())
+	}
+}
+
+func TestRedactPatternTemplate(t *testing.T) {
+	r := &RuleRedactor{
+		Patterns: []*regexp.Regexp{
+			regexp.MustCompile(`^\d+`),
+			regexp.MustCompile(`\bkey-\w+`),
+			regexp.MustCompile(`(?P<keep>pin:)\d+`),
+		},
+		Replacement: "$x",
+	}
+	if msg := r.RedactMessage("123 key-abc pin:42 monkey-1"); msg != "$x $x pin:$x monkey-1" {
+		t.Fatal("invalid redaction", msg)
+	}
+}
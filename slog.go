@@ -0,0 +1,196 @@
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sort"
+)
+
+const (
+	slogMessageKey = "message"
+	slogSourceKey  = "source"
+	slogCallerKey  = "caller"
+	slogFieldsKey  = "fields"
+	slogCodeKey    = "code"
+)
+
+type (
+	// SlogHandler is a slog.Handler which expands the error attributes
+	// into the traces which JSONWithStack produces.
+	SlogHandler struct {
+		handler    slog.Handler
+		stackCount int
+	}
+)
+
+// LogValue implements slog.LogValuer interface.
+func (e *errorType) LogValue() slog.Value {
+	return logValue(e, e.info)
+}
+
+// LogValue implements slog.LogValuer interface.
+func (l *leafError) LogValue() slog.Value {
+	return (*errorType)(l).LogValue()
+}
+
+// LogValue implements slog.LogValuer interface.
+func (e *errorSource) LogValue() slog.Value {
+	return logValue(e, e.info)
+}
+
+// LogValue implements slog.LogValuer interface.
+func (c *collection) LogValue() slog.Value {
+	return logValue(c, nil)
+}
+
+// logValue returns a group which has the message, the source,
+// the code, the first caller and the fields of the err.
+// They are redacted by the default printer.
+func logValue(err error, info *callerInfo) slog.Value {
+	p := DefaultPrinter()
+	attrs := []slog.Attr{
+		slog.String(slogMessageKey, p.redactMessage(messageChain(err))),
+	}
+	if source := SourceOf(err); source != nil {
+		attrs = append(attrs, slog.String(slogSourceKey, p.redactMessage(source.Error())))
+	}
+	if code := CodeOf(err); code != "" {
+		attrs = append(attrs, slog.String(slogCodeKey, string(code)))
+	}
+	if info != nil {
+		if location := p.location(info); location != "" {
+			attrs = append(attrs, slog.String(slogCallerKey, location))
+		}
+	}
+	if fields := Fields(err); fields != nil {
+		attrs = append(attrs, slog.Attr{
+			Key:   slogFieldsKey,
+			Value: fieldsLogValue(p.redactFields(fields)),
+		})
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func fieldsLogValue(fields map[string]interface{}) slog.Value {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	attrs := make([]slog.Attr, len(keys))
+	for index, key := range keys {
+		attrs[index] = slog.Any(key, fields[key])
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// NewSlogHandler returns a new SlogHandler which expands the errors
+// with stackCount frames by the default printer and passes the records to the handler.
+func NewSlogHandler(handler slog.Handler, stackCount int) *SlogHandler {
+	return &SlogHandler{
+		handler:    handler,
+		stackCount: stackCount,
+	}
+}
+
+// Enabled implements slog.Handler interface.
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler interface.
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	expanded := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		expanded.AddAttrs(h.expand(a))
+		return true
+	})
+	return h.handler.Handle(ctx, expanded)
+}
+
+// WithAttrs implements slog.Handler interface.
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for index, a := range attrs {
+		expanded[index] = h.expand(a)
+	}
+	return NewSlogHandler(h.handler.WithAttrs(expanded), h.stackCount)
+}
+
+// WithGroup implements slog.Handler interface.
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	return NewSlogHandler(h.handler.WithGroup(name), h.stackCount)
+}
+
+func (h *SlogHandler) expand(a slog.Attr) slog.Attr {
+	kind := a.Value.Kind()
+	if kind == slog.KindGroup {
+		group := a.Value.Group()
+		expanded := make([]slog.Attr, len(group))
+		for index, member := range group {
+			expanded[index] = h.expand(member)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(expanded...)}
+	}
+	if kind != slog.KindAny && kind != slog.KindLogValuer {
+		return a
+	}
+
+	err, ok := a.Value.Any().(error)
+	if !ok || err == nil {
+		return a
+	}
+	s, marshalErr := JSONWithStack(err, h.stackCount)
+	if marshalErr != nil {
+		return a
+	}
+	return slog.Attr{Key: a.Key, Value: jsonLogValue(json.RawMessage(s))}
+}
+
+// jsonLogValue converts the JSON to slog.Value keeping the order of the keys.
+// The objects become groups and the arrays are kept as raw JSON.
+func jsonLogValue(raw json.RawMessage) slog.Value {
+	trimmed := bytes.TrimSpace(raw)
+	if isJSONNull(trimmed) {
+		return slog.AnyValue(nil)
+	}
+
+	switch trimmed[0] {
+	case '{':
+		dec := json.NewDecoder(bytes.NewReader(trimmed))
+		if _, err := dec.Token(); err != nil {
+			return slog.AnyValue(json.RawMessage(trimmed))
+		}
+		attrs := []slog.Attr{}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return slog.AnyValue(json.RawMessage(trimmed))
+			}
+			value := json.RawMessage{}
+			if err := dec.Decode(&value); err != nil {
+				return slog.AnyValue(json.RawMessage(trimmed))
+			}
+			attrs = append(attrs, slog.Attr{
+				Key:   key.(string),
+				Value: jsonLogValue(value),
+			})
+		}
+		return slog.GroupValue(attrs...)
+	case '[':
+		return slog.AnyValue(json.RawMessage(trimmed))
+	}
+
+	var i int64
+	if err := json.Unmarshal(trimmed, &i); err == nil {
+		return slog.Int64Value(i)
+	}
+	var v interface{}
+	if err := json.Unmarshal(trimmed, &v); err != nil {
+		return slog.AnyValue(json.RawMessage(trimmed))
+	}
+	return slog.AnyValue(v)
+}
@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLogValue(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(slog.NewJSONHandler(buf, nil))
+
+	e := Wrap(With(WrapBySourceMsg(io.EOF, "source message"), "userID", 10), "outer")
+	logger.Error("failed", slog.Any("err", e))
+
+	obj := struct {
+		Err struct {
+			Message string                 `json:"message"`
+			Source  string                 `json:"source"`
+			Caller  string                 `json:"caller"`
+			Fields  map[string]interface{} `json:"fields"`
+		} `json:"err"`
+	}{}
+	if err := json.Unmarshal(buf.Bytes(), &obj); err != nil {
+		t.Fatal(err, buf.String())
+	}
+	if obj.Err.Message != "outer: source message: EOF" {
+		t.Fatal("invalid message", buf.String())
+	}
+	if obj.Err.Source != "source message" {
+		t.Fatal("invalid source", buf.String())
+	}
+	if !strings.HasPrefix(obj.Err.Caller, "github.com/trimark-jp/errors/slog_test.go:") {
+		t.Fatal("invalid caller", buf.String())
+	}
+	if obj.Err.Fields["userID"] != float64(10) {
+		t.Fatal("invalid fields", buf.String())
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	logger := slog.New(NewSlogHandler(slog.NewJSONHandler(buf, nil), 2))
+
+	e := Wrap(New("inner"), "outer")
+	logger.With("base", Merge(io.EOF, New("merged"))).
+		WithGroup("request").
+		Error("failed", slog.Any("err", e), slog.Int("status", 500))
+
+	expected, _ := JSONWithStack(e, 2)
+	obj := struct {
+		Base    json.RawMessage `json:"base"`
+		Request struct {
+			Err    json.RawMessage `json:"err"`
+			Status int             `json:"status"`
+		} `json:"request"`
+	}{}
+	if err := json.Unmarshal(buf.Bytes(), &obj); err != nil {
+		t.Fatal(err, buf.String())
+	}
+	if string(obj.Request.Err) != expected {
+		t.Fatal("error not expanded", buf.String())
+	}
+	if obj.Request.Status != 500 {
+		t.Fatal("invalid status", buf.String())
+	}
+	if !strings.HasPrefix(string(obj.Base), `{"errors":[{"message":"EOF"},`) {
+		t.Fatal("error of attrs not expanded", buf.String())
+	}
+}
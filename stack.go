@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+)
+
+const (
+	// stackGoroutineID is the goroutine ID of StackString.
+	// The goroutine which captured the callers is not recorded.
+	stackGoroutineID = 1
+
+	stackHeaderFormat   = "goroutine %d [running]:\n"
+	stackFunctionFormat = "%s(...)\n"
+	stackFileFormat     = "\t%s:%d\n"
+	stackPanicFormat    = "panic: %s\n\n%s"
+)
+
+// WithPanicStackFormat returns a new Printer whose %+v prints the message and
+// the stack of the innermost error in the format of the Go panics like StackString,
+// instead of StringWithInner.
+// It takes effect on the default printer.
+func (p *Printer) WithPanicStackFormat(enable bool) *Printer {
+	result := p.clone()
+	result.panicStack = enable
+	return result
+}
+
+// StackString returns the stack of the innermost error with callers
+// in the format of the Go panics, so the tools for the panics such as panicparse read it.
+//
+//	goroutine 1 [running]:
+//	main.load(...)
+//		/home/user/app/main.go:12
+//	main.main(...)
+//		/home/user/app/main.go:20
+//
+// The file paths are absolute if they are known,
+// the errors parsed from JSON have only the trimmed paths.
+// The goroutine ID is always 1, the goroutine of the callers is not recorded.
+// Returns "" if no error has callers.
+func (p *Printer) StackString(err error) string {
+	info := innermostStack(err)
+	if info == nil {
+		return ""
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, stackHeaderFormat, stackGoroutineID)
+	for _, item := range p.frames(info, p.stackCount()) {
+		file := item.File
+		if item.path != "" {
+			file = p.redactPath(item.path)
+		}
+		fmt.Fprintf(buf, stackFunctionFormat, item.Function)
+		fmt.Fprintf(buf, stackFileFormat, file, item.Line)
+	}
+	return buf.String()
+}
+
+// panicString returns the message and the stack of the err
+// as the output of the Go panics.
+func (p *Printer) panicString(err error) string {
+	return fmt.Sprintf(stackPanicFormat, p.redactMessage(messageChain(err)), p.StackString(err))
+}
+
+// innermostStack returns the callers of the innermost error which has the callers.
+// The first errors of the collections and the foreign wrappers are followed.
+func innermostStack(err error) *callerInfo {
+	var result *callerInfo
+	for err != nil {
+		info := callerInfoOf(err)
+		if info == nil {
+			info = foreignStackOf(err)
+		}
+		if info != nil && 0 < len(info.Items()) {
+			result = info
+		}
+		err = firstInnerOf(err)
+	}
+	return result
+}
+
+// firstInnerOf returns the inner error of the err,
+// or the first error of the collection or the foreign wrapper.
+func firstInnerOf(err error) error {
+	if e, ok := errorTypeOf(err); ok {
+		return e.inner
+	}
+	if e, ok := err.(*errorSource); ok {
+		return e.inner
+	}
+	if c, ok := err.(*collection); ok {
+		if len(c.errs) <= 0 {
+			return nil
+		}
+		return c.errs[0]
+	}
+	if inners := unwrapForeign(err); inners != nil {
+		return inners[0]
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var (
+	stackFramePattern = regexp.MustCompile(`^[^\s]+\(\.\.\.\)\n\t(.+):(\d+)$`)
+)
+
+func stackInner() error {
+	return New("inner")
+}
+
+func TestStackString(t *testing.T) {
+	e := Wrap(fmt.Errorf("wrapped: %w", stackInner()), "outer")
+	s := StackString(e)
+
+	if !strings.HasPrefix(s, "goroutine 1 [running]:\ngithub.com/trimark-jp/errors.stackInner(...)\n\t") {
+		t.Fatal("invalid stack", s)
+	}
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for index := 1; index+1 < len(lines); index += 2 {
+		match := stackFramePattern.FindStringSubmatch(lines[index] + "\n" + lines[index+1])
+		if match == nil {
+			t.Fatal("invalid frame", lines[index:index+2])
+		}
+		if !filepath.IsAbs(match[1]) {
+			t.Fatal("path is not absolute", match[1])
+		}
+	}
+
+	if StackString(io.EOF) != "" || StackString(nil) != "" {
+		t.Fatal("stack of error without callers")
+	}
+}
+
+func TestPanicStackFormat(t *testing.T) {
+	defer SetDefaultPrinter(nil)
+	e := Wrap(stackInner(), "outer")
+
+	if s := fmt.Sprintf("%+v", e); strings.HasPrefix(s, "panic: ") {
+		t.Fatal("panic format by default", s)
+	}
+
+	SetDefaultPrinter(NewPrinter().WithPanicStackFormat(true))
+	s := fmt.Sprintf("%+v", e)
+	if !strings.HasPrefix(s, "panic: outer: inner\n\ngoroutine 1 [running]:\ngithub.com/trimark-jp/errors.stackInner(...)\n") {
+		t.Fatal("invalid panic format", s)
+	}
+}
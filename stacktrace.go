@@ -0,0 +1,191 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+type (
+	// Frame is the program counter of a frame.
+	// It is compatible with Frame of github.com/pkg/errors.
+	Frame uintptr
+
+	// StackTrace is the frames from the innermost.
+	// It is compatible with StackTrace of github.com/pkg/errors,
+	// so the tools such as Sentry find the frames of the errors.
+	StackTrace []Frame
+)
+
+const (
+	stackTraceMethod = "StackTrace"
+	unknownFrame     = "unknown"
+)
+
+// Cause returns the inner error.
+// It is compatible with Cause of github.com/pkg/errors.
+// The errors without the inner error are leafError which has no Cause,
+// so Cause of github.com/pkg/errors returns them as they are.
+func (e *errorType) Cause() error {
+	return e.inner
+}
+
+// Cause returns the source error.
+func (e *errorSource) Cause() error {
+	return e.source
+}
+
+// Cause returns the source of the collection.
+func (c *collection) Cause() error {
+	return c.source()
+}
+
+// StackTrace returns the frames of the callers.
+// It is compatible with StackTrace of github.com/pkg/errors.
+// The errors parsed from JSON have no frames.
+func (e *errorType) StackTrace() StackTrace {
+	if e.info == nil {
+		return nil
+	}
+	result := make(StackTrace, len(e.info.pcs))
+	for index, pc := range e.info.pcs {
+		result[index] = Frame(pc)
+	}
+	return result
+}
+
+// StackTrace returns the frames of the callers.
+func (l *leafError) StackTrace() StackTrace {
+	return (*errorType)(l).StackTrace()
+}
+
+// Format implements fmt.Formatter interface as Frame of github.com/pkg/errors.
+//
+//	%s    the file name
+//	%d    the line
+//	%n    the function name
+//	%v    %s:%d
+//	%+s   the function and the file path
+//	%+v   %+s:%d
+func (f Frame) Format(s fmt.State, verb rune) {
+	item := frameOf(uintptr(f))
+	switch verb {
+	case 's':
+		if s.Flag('+') {
+			io.WriteString(s, f.function(item))
+			io.WriteString(s, "\n\t")
+			io.WriteString(s, f.file(item))
+			return
+		}
+		io.WriteString(s, path.Base(f.file(item)))
+	case 'd':
+		io.WriteString(s, strconv.Itoa(item.Line))
+	case 'n':
+		io.WriteString(s, shortFunctionName(f.function(item)))
+	case 'v':
+		f.Format(s, 's')
+		io.WriteString(s, ":")
+		f.Format(s, 'd')
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler interface as Frame of github.com/pkg/errors.
+func (f Frame) MarshalText() ([]byte, error) {
+	item := frameOf(uintptr(f))
+	if item.Function == "" {
+		return []byte(unknownFrame), nil
+	}
+	return []byte(fmt.Sprintf("%s %s:%d", item.Function, f.file(item), item.Line)), nil
+}
+
+func (f Frame) function(item *callerInfoItem) string {
+	if item.Function == "" {
+		return unknownFrame
+	}
+	return item.Function
+}
+
+func (f Frame) file(item *callerInfoItem) string {
+	if item.path == "" {
+		return unknownFrame
+	}
+	return item.path
+}
+
+// Format implements fmt.Formatter interface as StackTrace of github.com/pkg/errors.
+//
+//	%s    the file names of the frames
+//	%v    the file names and the lines of the frames
+//	%+v   the functions, the file paths and the lines of the frames
+//	%#v   Go-syntax representation
+func (st StackTrace) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			for _, f := range st {
+				io.WriteString(s, "\n")
+				f.Format(s, verb)
+			}
+			return
+		}
+		if s.Flag('#') {
+			fmt.Fprintf(s, "%#v", []Frame(st))
+			return
+		}
+		st.formatSlice(s, verb)
+	case 's':
+		st.formatSlice(s, verb)
+	}
+}
+
+func (st StackTrace) formatSlice(s fmt.State, verb rune) {
+	io.WriteString(s, "[")
+	for index, f := range st {
+		if 0 < index {
+			io.WriteString(s, " ")
+		}
+		f.Format(s, verb)
+	}
+	io.WriteString(s, "]")
+}
+
+// shortFunctionName returns the function name without the package path.
+func shortFunctionName(function string) string {
+	function = function[strings.LastIndex(function, "/")+1:]
+	return function[strings.Index(function, ".")+1:]
+}
+
+// foreignStackOf returns the caller info of the foreign err
+// which has the StackTrace method such as the errors of github.com/pkg/errors.
+// The method is found by reflection so as not to import the package,
+// it must return a slice of the program counters.
+// Returns nil if the err has no stack trace.
+func foreignStackOf(err error) *callerInfo {
+	if w, ok := err.(*parsedWrapper); ok {
+		return w.info
+	}
+
+	method := reflect.ValueOf(err).MethodByName(stackTraceMethod)
+	if !method.IsValid() {
+		return nil
+	}
+	t := method.Type()
+	if t.NumIn() != 0 || t.NumOut() != 1 || t.Out(0).Kind() != reflect.Slice || t.Out(0).Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+
+	frames := method.Call(nil)[0]
+	if frames.Len() <= 0 {
+		return nil
+	}
+	pcs := make([]uintptr, frames.Len())
+	for index := range pcs {
+		pcs[index] = uintptr(frames.Index(index).Uint())
+	}
+	return &callerInfo{
+		pcs: pcs,
+	}
+}
@@ -0,0 +1,137 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+type (
+	// pkgFrame, pkgStackTrace and pkgError mimic the errors of github.com/pkg/errors.
+	pkgFrame      uintptr
+	pkgStackTrace []pkgFrame
+	pkgError      struct {
+		msg   string
+		cause error
+		stack []uintptr
+	}
+)
+
+func newPkgError(msg string, cause error) error {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	return &pkgError{msg: msg, cause: cause, stack: pcs[:n]}
+}
+
+func (e *pkgError) Error() string {
+	return e.msg
+}
+
+func (e *pkgError) Unwrap() error {
+	return e.cause
+}
+
+func (e *pkgError) StackTrace() pkgStackTrace {
+	result := make(pkgStackTrace, len(e.stack))
+	for index, pc := range e.stack {
+		result[index] = pkgFrame(pc)
+	}
+	return result
+}
+
+// pkgCause is Cause of github.com/pkg/errors.
+func pkgCause(err error) error {
+	type causer interface {
+		Cause() error
+	}
+	for err != nil {
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+		err = cause.Cause()
+	}
+	return err
+}
+
+func TestCause(t *testing.T) {
+	if pkgCause(Wrap(io.EOF, "outer")) != io.EOF {
+		t.Fatal("invalid cause")
+	}
+	if pkgCause(WrapBySourceError(New("inner"), io.ErrUnexpectedEOF)) != io.ErrUnexpectedEOF {
+		t.Fatal("invalid cause of source")
+	}
+	if pkgCause(Merge(io.EOF, io.ErrUnexpectedEOF)) != io.EOF {
+		t.Fatal("invalid cause of collection")
+	}
+
+	sentinel := New("sentinel")
+	if cause := pkgCause(Wrap(Wrap(sentinel, "middle"), "outer")); cause != sentinel {
+		t.Fatal("invalid root cause", cause)
+	}
+	if cause := pkgCause(sentinel); cause != sentinel {
+		t.Fatal("invalid cause of leaf", cause)
+	}
+	if cause := pkgCause(SourceOf(Wrap(sentinel, "x"))); cause != sentinel {
+		t.Fatal("invalid cause of source", cause)
+	}
+	if cause := pkgCause(NewCode(codeTestNotFound, "")); cause == nil {
+		t.Fatal("nil cause of leaf")
+	}
+	if !stderrors.Is(Wrap(sentinel, "outer"), sentinel) {
+		t.Fatal("sentinel not found")
+	}
+}
+
+func TestStackTrace(t *testing.T) {
+	e := New("error").(*leafError)
+	st := e.StackTrace()
+	if len(st) <= 0 {
+		t.Fatal("empty stack trace")
+	}
+
+	if s := fmt.Sprintf("%n", st[0]); s != "TestStackTrace" {
+		t.Fatal("invalid function name", s)
+	}
+	if s := fmt.Sprintf("%v", st[0]); !strings.HasPrefix(s, "stacktrace_test.go:") {
+		t.Fatal("invalid frame", s)
+	}
+	if s := fmt.Sprintf("%+v", st); !strings.HasPrefix(s, "\ngithub.com/trimark-jp/errors.TestStackTrace\n\t/") {
+		t.Fatal("invalid stack trace", s)
+	}
+	if s := fmt.Sprintf("%s", st[:1]); !strings.HasPrefix(s, "[stacktrace_test.go]") {
+		t.Fatal("invalid frames", s)
+	}
+
+	parsed, _ := ParseJSON(`{"inner":null,"callers":[],"message":"parsed"}`)
+	if st := parsed.(*leafError).StackTrace(); len(st) != 0 {
+		t.Fatal("stack trace of parsed error", st)
+	}
+}
+
+func TestForeignStackTrace(t *testing.T) {
+	e := Wrap(newPkgError("wrapped", newPkgError("fundamental", nil)), "outer")
+
+	lines := strings.Split(StringWithInner(e), "\n")
+	if !strings.Contains(lines[1], "stacktrace_test.go:") || !strings.Contains(lines[1], "TestForeignStackTrace\twrapped\t(*errors.pkgError)") {
+		t.Fatal("invalid foreign wrapper", lines)
+	}
+	if !strings.Contains(lines[2], "TestForeignStackTrace\tfundamental") {
+		t.Fatal("invalid foreign leaf", lines)
+	}
+
+	s, _ := JSONAll(e)
+	parsed, err := ParseJSON(s)
+	if err != nil {
+		t.Fatal("parse failed", err)
+	}
+	if StringWithInner(parsed) != StringWithInner(e) {
+		t.Fatal("not round-tripped", StringWithInner(parsed), s)
+	}
+	if foreignStackOf(io.EOF) != nil {
+		t.Fatal("stack trace of plain error")
+	}
+}
@@ -1,92 +1,62 @@
 package errors
 
 import (
-	"bytes"
 	"encoding/json"
-	"fmt"
 )
 
 var (
 	// StringWithLocationFormat is the format for StringWithLocation.
+	//
+	// Deprecated: Use Printer.WithLocationFormat and SetDefaultPrinter.
 	StringWithLocationFormat = "%s\t%s"
 
 	// StringWithInnerIndent is indent for inner errors.
+	//
+	// Deprecated: Use Printer.WithIndent and SetDefaultPrinter.
 	StringWithInnerIndent = "\t"
 )
 
 // JSON returns a json string which has error trace.
 func JSON(err error) (string, error) {
-	return JSONWithStack(err, 1)
+	return DefaultPrinter().JSON(err)
 }
 
 // JSONAll returns a json string which has error trace.
 func JSONAll(err error) (string, error) {
-	return JSONWithStack(err, CallerInfoMaxStack)
+	return DefaultPrinter().JSONAll(err)
 }
 
 // JSONWithStack returns a json string which has error trace.
 func JSONWithStack(err error, stackCount int) (string, error) {
-	if err == nil {
-		return "", nil
+	return DefaultPrinter().JSONWithStack(err, stackCount)
+}
+
+// ParseJSON returns an error rebuilt from the output of JSON, JSONAll or JSONWithStack.
+// The rebuilt error keeps the inner errors, the callers, the source and the merged errors,
+// so SourceOf, StringWithInner and Merge work on it.
+func ParseJSON(s string) (error, error) {
+	if s == "" {
+		return nil, nil
 	}
 
-	em := &errMarshal{
-		err: err,
+	em := &errMarshal{}
+	if err := json.Unmarshal([]byte(s), em); err != nil {
+		return nil, err
 	}
-	em.setCallerCount(stackCount)
-	b, e := json.Marshal(em)
-	return string(b), e
+	return em.err, nil
 }
 
 // StringWithLocation returns error location and error message as string.
 func StringWithLocation(err error) string {
-	if err == nil {
-		return ""
-	}
-
-	if e, ok := err.(*errorType); ok {
-		return fmt.Sprintf(StringWithLocationFormat, e.info.String(), e.Error())
-	}
-	if e, ok := err.(*errorSource); ok {
-		return fmt.Sprintf(StringWithLocationFormat, e.info.String(), e.Error())
-	}
-	if c, ok := err.(*collection); ok {
-		return c.StringWithLocation()
-	}
-	return err.Error()
+	return DefaultPrinter().StringWithLocation(err)
 }
 
 // StringWithInner inner returns string representation of the error and inner errors.
 func StringWithInner(err error) string {
-	if err == nil {
-		return ""
-	}
-	return stringWithInner(err, "")
+	return DefaultPrinter().StringWithInner(err)
 }
 
-func stringWithInner(err error, indent string) string {
-	buf := &bytes.Buffer{}
-
-	if e, ok := err.(*errorType); ok {
-		fmt.Fprintln(buf, indent+StringWithLocation(err))
-		if e.inner != nil {
-			fmt.Fprint(buf, stringWithInner(e.inner, indent+StringWithInnerIndent))
-		}
-		return buf.String()
-	}
-	if e, ok := err.(*errorSource); ok {
-		fmt.Fprintln(buf, indent+StringWithLocation(err))
-		if e.inner != nil {
-			fmt.Fprint(buf, stringWithInner(e.inner, indent+StringWithInnerIndent))
-		}
-		return buf.String()
-	}
-
-	if c, ok := err.(*collection); ok {
-		fmt.Fprint(buf, c.stringWithInner(indent+StringWithInnerIndent))
-		return buf.String()
-	}
-
-	fmt.Fprintln(buf, indent+StringWithLocation(err))
-	return buf.String()
+// StackString returns the stack of the innermost error in the format of the Go panics.
+func StackString(err error) string {
+	return DefaultPrinter().StackString(err)
 }